@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,9 +13,11 @@ import (
 	_ "gymapp/docs"
 	"gymapp/internal/config"
 	"gymapp/internal/database"
+	"gymapp/internal/domain"
 	httphandler "gymapp/internal/handler/http"
 	midauth "gymapp/internal/middleware"
 	"gymapp/internal/repository/postgres"
+	"gymapp/internal/scheduler"
 	"gymapp/internal/service"
 	"gymapp/pkg/utils"
 
@@ -96,12 +99,71 @@ func main() {
 	recipeRepo := postgres.NewRecipeRepository(pool)
 	trainingRepo := postgres.NewTrainingRepository(pool)
 	refreshTokenRepo := postgres.NewRefreshTokenRepository(pool)
+	workoutRepo := postgres.NewWorkoutRepository(pool)
+	measurementRepo := postgres.NewBodyMeasurementRepository(pool)
+	exerciseRepo := postgres.NewExerciseRepository(pool)
+	diaryRepo := postgres.NewFoodDiaryRepository(pool)
+	mealPlanRepo := postgres.NewMealPlanRepository(pool)
+	shoppingListRepo := postgres.NewShoppingListRepository(pool)
+	jobRepo := postgres.NewJobRepository(pool)
+	aiCacheRepo := postgres.NewAICacheRepository(pool)
+	aiUsageRepo := postgres.NewAIUsageRepository(pool)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, refreshTokenRepo, &cfg.JWT)
-	aiService := service.NewAIService(&cfg.AI)
-	recipeService := service.NewRecipeService(recipeRepo, aiService)
-	trainingService := service.NewTrainingService(trainingRepo, userRepo, aiService)
+	userService := service.NewUserService(userRepo)
+	var aiCacheStore domain.AICacheRepository
+	if cfg.AI.CachePersist {
+		aiCacheStore = aiCacheRepo
+	}
+	aiProvider, err := service.NewAIProvider(&cfg.AI, aiCacheStore)
+	if err != nil {
+		logger.Errorf("❌ AI provider setup failed: %v", err)
+		fmt.Printf("❌ Failed to configure AI provider: %v\n", err)
+		os.Exit(1)
+	}
+	usageService := service.NewUsageService(aiUsageRepo, &cfg.AI, logger)
+	nutritionService := service.NewNutritionService(userRepo, measurementRepo)
+	recipeService := service.NewRecipeService(recipeRepo, userRepo, nutritionService, aiProvider, usageService)
+	trainingService := service.NewTrainingService(trainingRepo, userRepo, measurementRepo, exerciseRepo, nutritionService, aiProvider, usageService)
+	workoutService := service.NewWorkoutService(workoutRepo, exerciseRepo)
+	measurementService := service.NewBodyMeasurementService(measurementRepo)
+	exerciseService := service.NewExerciseService(exerciseRepo)
+	diaryService := service.NewFoodDiaryService(diaryRepo, recipeRepo, nutritionService)
+	mealPlanService := service.NewMealPlanService(mealPlanRepo, userRepo, nutritionService, aiProvider, usageService)
+	shoppingListService := service.NewShoppingListService(shoppingListRepo, recipeRepo, mealPlanRepo)
+	jobService := service.NewJobService(jobRepo, &cfg.Jobs, logger)
+	jobService.Register(domain.JobKindRecipe, recipeService.RunTextJob)
+	jobService.Register(domain.JobKindTrainingPlan, trainingService.RunPlanJob)
+	jobService.Register(domain.JobKindMealPlan, mealPlanService.RunJob)
+
+	// Background jobs
+	jobs := scheduler.New(database.NewAdvisoryLocker(pool), logger)
+	jobs.Register(scheduler.Job{
+		Name:     "purge-expired-refresh-tokens",
+		Interval: cfg.Scheduler.TokenCleanupInterval,
+		Run:      refreshTokenRepo.DeleteExpiredTokens,
+	})
+	jobs.Register(scheduler.Job{
+		Name:     "purge-finished-generation-jobs",
+		Interval: cfg.Scheduler.JobPurgeInterval,
+		Run:      jobService.PurgeFinished,
+	})
+	if aiCacheStore != nil {
+		jobs.Register(scheduler.Job{
+			Name:     "purge-expired-ai-cache",
+			Interval: cfg.Scheduler.AICachePurgeInterval,
+			Run:      aiCacheStore.DeleteExpired,
+		})
+	}
+
+	if cfg.Scheduler.Enabled {
+		jobs.Start(context.Background())
+		logger.Infof("⏱️  Background scheduler started")
+	}
+
+	jobService.Start(context.Background())
+	logger.Infof("⚙️  Generation workers started (%d)", cfg.Jobs.Workers)
 
 	// Setup Echo
 	e := echo.New()
@@ -110,7 +172,7 @@ func main() {
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
 	}))
 
 	// Register Swagger routes
@@ -118,14 +180,42 @@ func main() {
 
 	// Routes
 	httphandler.RegisterHealthRoutes(e)
-	httphandler.RegisterAuthRoutes(e, authService)
 
 	authMiddleware := midauth.JWTAuth(authService)
-	httphandler.RegisterRecipeRoutes(e, authMiddleware, recipeService)
-	httphandler.RegisterTrainingRoutes(e, authMiddleware, trainingService)
+	httphandler.RegisterAuthRoutes(e, authMiddleware, authService)
+	httphandler.RegisterUserRoutes(e, authMiddleware, userService)
+	httphandler.RegisterUsageRoutes(e, authMiddleware, usageService)
+	httphandler.RegisterRecipeRoutes(e, authMiddleware, recipeService, jobService)
+	httphandler.RegisterTrainingRoutes(e, authMiddleware, trainingService, jobService)
+	httphandler.RegisterWorkoutRoutes(e, authMiddleware, workoutService)
+	httphandler.RegisterBodyMeasurementRoutes(e, authMiddleware, measurementService)
+	httphandler.RegisterExerciseRoutes(e, authMiddleware, exerciseService)
+	httphandler.RegisterNutritionRoutes(e, authMiddleware, nutritionService)
+	httphandler.RegisterFoodDiaryRoutes(e, authMiddleware, diaryService)
+	httphandler.RegisterMealPlanRoutes(e, authMiddleware, mealPlanService, jobService)
+	httphandler.RegisterShoppingListRoutes(e, authMiddleware, shoppingListService)
+	httphandler.RegisterJobRoutes(e, authMiddleware, jobService)
+
+	// Runtime metrics, including AI cache hits and misses, stay off the
+	// public port since they expose the command line and memory stats.
+	var debugServer *http.Server
+	if cfg.Server.DebugAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		debugServer = &http.Server{Addr: cfg.Server.DebugAddr, Handler: mux}
+		go func() {
+			if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Errorf("debug server error: %v", err)
+			}
+		}()
+		logger.Infof("🔍 Debug metrics listening on %s", cfg.Server.DebugAddr)
+	}
 
 	// Graceful shutdown
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
+
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		<-sigChan
@@ -140,7 +230,12 @@ func main() {
 			logger.Errorf("error during shutdown: %v", err)
 			fmt.Printf("Error during shutdown: %v\n", err)
 		}
+		if debugServer != nil {
+			debugServer.Shutdown(shutdownCtx)
+		}
 
+		jobs.Stop()
+		jobService.Stop()
 		pool.Close()
 	}()
 
@@ -161,4 +256,6 @@ func main() {
 		fmt.Printf("❌ Server error: %v\n", err)
 		os.Exit(1)
 	}
+
+	<-shutdownDone
 }
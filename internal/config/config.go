@@ -8,15 +8,21 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	AI       AIConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	AI        AIConfig
+	Scheduler SchedulerConfig
+	Jobs      JobsConfig
 }
 
+// ServerConfig sets where the API listens. DebugAddr, when set, serves
+// runtime metrics at /debug/vars on a separate listener that should only be
+// reachable from inside the deployment.
 type ServerConfig struct {
-	Port int
-	Env  string
+	Port      int
+	Env       string
+	DebugAddr string
 }
 
 type DatabaseConfig struct {
@@ -34,17 +40,67 @@ type JWTConfig struct {
 	Secret          string
 }
 
+// AIConfig selects the provider. Calls that fail because the provider is
+// unavailable are retried up to MaxRetries times with backoff between
+// RetryBaseDelay and RetryMaxDelay; after BreakerThreshold consecutive
+// failures calls fail fast for BreakerCooldown. Replies are cached for
+// CacheTTL in an in-memory LRU of CacheSize entries, and in Postgres too when
+// CachePersist is set. Each user may spend DailyTokenQuota tokens per UTC
+// day and MonthlyTokenQuota per UTC month; 0 means no limit. Prompt
+// templates are embedded, and templates in PromptDir add or replace
+// versions; RecipePrompt and TrainingPrompt select the version, or weighted
+// versions such as "v1:90,v2:10" to compare them.
 type AIConfig struct {
-	APIKey  string
-	BaseURL string
-	Model   string
+	Provider    string
+	APIKey      string
+	BaseURL     string
+	Model       string
+	VisionModel string
+
+	MaxRetries       int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	CacheEnabled bool
+	CacheTTL     time.Duration
+	CacheSize    int
+	CachePersist bool
+
+	DailyTokenQuota   int
+	MonthlyTokenQuota int
+
+	PromptDir      string
+	RecipePrompt   string
+	TrainingPrompt string
+}
+
+// SchedulerConfig sets how often each periodic cleanup runs.
+type SchedulerConfig struct {
+	Enabled              bool
+	TokenCleanupInterval time.Duration
+	JobPurgeInterval     time.Duration
+	AICachePurgeInterval time.Duration
+}
+
+// JobsConfig tunes the workers that run queued AI generations. Lease is how
+// long a claimed job stays locked before another worker may take it over;
+// handlers are cancelled before it runs out.
+type JobsConfig struct {
+	Workers      int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MaxAttempts  int
+	Retention    time.Duration
 }
 
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getIntEnv("SERVER_PORT", 8080),
-			Env:  getEnv("ENV", "development"),
+			Port:      getIntEnv("SERVER_PORT", 8080),
+			Env:       getEnv("ENV", "development"),
+			DebugAddr: getEnv("DEBUG_ADDR", ""),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -59,14 +115,62 @@ func Load() *Config {
 			RefreshTokenTTL: 7 * 24 * time.Hour,
 			Secret:          getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
 		},
-		AI: AIConfig{
-			APIKey:  getEnv("AI_API_KEY", ""),
-			BaseURL: getEnv("AI_BASE_URL", "https://api.openai.com/v1"),
-			Model:   getEnv("AI_MODEL", "gpt-3.5-turbo"),
+		AI: loadAIConfig(),
+		Scheduler: SchedulerConfig{
+			Enabled:              getBoolEnv("SCHEDULER_ENABLED", true),
+			TokenCleanupInterval: getDurationEnv("TOKEN_CLEANUP_INTERVAL", time.Hour),
+			JobPurgeInterval:     getDurationEnv("JOB_PURGE_INTERVAL", time.Hour),
+			AICachePurgeInterval: getDurationEnv("AI_CACHE_PURGE_INTERVAL", time.Hour),
+		},
+		Jobs: JobsConfig{
+			Workers:      getIntEnv("JOB_WORKERS", 2),
+			PollInterval: getDurationEnv("JOB_POLL_INTERVAL", time.Second),
+			Lease:        getDurationEnv("JOB_LEASE", 2*time.Minute),
+			MaxAttempts:  getIntEnv("JOB_MAX_ATTEMPTS", 3),
+			Retention:    getDurationEnv("JOB_RETENTION", 7*24*time.Hour),
 		},
 	}
 }
 
+func loadAIConfig() AIConfig {
+	provider := getEnv("AI_PROVIDER", "")
+
+	baseURL := "https://api.openai.com/v1"
+	model := "gpt-3.5-turbo"
+	visionModel := "gpt-4o-mini"
+	if provider == "ollama" {
+		baseURL = "http://localhost:11434"
+		model = "llama3"
+		visionModel = "llava"
+	}
+
+	return AIConfig{
+		Provider:    provider,
+		APIKey:      getEnv("AI_API_KEY", ""),
+		BaseURL:     getEnv("AI_BASE_URL", baseURL),
+		Model:       getEnv("AI_MODEL", model),
+		VisionModel: getEnv("AI_VISION_MODEL", visionModel),
+
+		MaxRetries:       getIntEnv("AI_MAX_RETRIES", 2),
+		RetryBaseDelay:   getDurationEnv("AI_RETRY_BASE_DELAY", 500*time.Millisecond),
+		RetryMaxDelay:    getDurationEnv("AI_RETRY_MAX_DELAY", 10*time.Second),
+		BreakerThreshold: getIntEnv("AI_BREAKER_THRESHOLD", 5),
+		BreakerCooldown:  getDurationEnv("AI_BREAKER_COOLDOWN", 30*time.Second),
+
+		CacheEnabled: getBoolEnv("AI_CACHE_ENABLED", true),
+		CacheTTL:     getDurationEnv("AI_CACHE_TTL", 24*time.Hour),
+		CacheSize:    getIntEnv("AI_CACHE_SIZE", 500),
+		CachePersist: getBoolEnv("AI_CACHE_PERSIST", false),
+
+		DailyTokenQuota:   getIntEnv("AI_DAILY_TOKEN_QUOTA", 100000),
+		MonthlyTokenQuota: getIntEnv("AI_MONTHLY_TOKEN_QUOTA", 1000000),
+
+		PromptDir:      getEnv("AI_PROMPT_DIR", ""),
+		RecipePrompt:   getEnv("AI_RECIPE_PROMPT", "v1"),
+		TrainingPrompt: getEnv("AI_TRAINING_PROMPT", "v1"),
+	}
+}
+
 func (c DatabaseConfig) DSN() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		c.User, c.Password, c.Host, c.Port, c.DBName, c.SSLMode)
@@ -87,3 +191,21 @@ func getIntEnv(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durVal, err := time.ParseDuration(value); err == nil && durVal > 0 {
+			return durVal
+		}
+	}
+	return defaultValue
+}
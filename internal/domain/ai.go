@@ -0,0 +1,104 @@
+package domain
+
+import "context"
+
+const (
+	AIProviderOpenAI = "openai"
+	AIProviderOllama = "ollama"
+	AIProviderMock   = "mock"
+)
+
+// AIProvider generates the free-form content behind recipes and training
+// plans. Implementations live in the service package and are selected by
+// config.AIConfig.
+type AIProvider interface {
+	GenerateRecipes(ctx context.Context, input *RecipeInput) (string, error)
+	GenerateTrainingPlan(ctx context.Context, input *TrainingPlanInput) (string, error)
+	GenerateMealPlan(ctx context.Context, input *MealPlanInput) (string, error)
+	// DetectIngredients lists the food ingredients visible in an image,
+	// adding the tokens spent to usage when it is not nil.
+	DetectIngredients(ctx context.Context, imageData []byte, mimeType string, usage *TokenUsage) ([]string, error)
+}
+
+// StreamFunc receives the reply text as the provider generates it. Returning
+// an error aborts the generation.
+type StreamFunc func(delta string) error
+
+// AICacheEntry is a stored provider reply. Key hashes the provider, model
+// and normalized prompt.
+type AICacheEntry struct {
+	Key       string
+	Provider  string
+	Model     string
+	Reply     string
+	CreatedAt int64
+	ExpiresAt int64
+}
+
+// AICacheRepository is the optional shared tier of the AI response cache.
+// Get returns ErrNotFound for missing and expired entries.
+type AICacheRepository interface {
+	Get(ctx context.Context, key string) (*AICacheEntry, error)
+	Set(ctx context.Context, entry *AICacheEntry) error
+	DeleteExpired(ctx context.Context) error
+}
+
+// RecipeInput is everything the provider needs to suggest recipes. Targets
+// is nil when the profile is too incomplete to compute them. When Stream is
+// set the reply is also passed to it piece by piece. SkipCache asks for a
+// fresh reply even when an identical prompt was answered before. Usage, when
+// set, accumulates the tokens the provider reports. Validate, when set,
+// checks the reply the way the caller will use it; replies it rejects are
+// not cached. UserID picks the prompt template version, and the provider
+// sets PromptVersion to the one it used.
+type RecipeInput struct {
+	UserID        int64
+	Ingredients   string
+	Goal          string
+	Targets       *NutritionTargets
+	Diet          DietaryPreferences
+	Stream        StreamFunc
+	SkipCache     bool
+	Usage         *TokenUsage
+	Validate      func(reply string) error
+	PromptVersion string
+}
+
+// TrainingPlanInput is everything the provider needs to write a plan.
+// Trend and Targets are nil when there are not enough measurements or
+// profile data. Exercises is the catalog the plan must draw from. Stream,
+// SkipCache, Usage, Validate, UserID and PromptVersion work as in
+// RecipeInput.
+type TrainingPlanInput struct {
+	UserID        int64
+	WeightKg      float64
+	HeightCm      int
+	TargetWeight  int
+	AvailableDays int
+	Trend         *WeightTrend
+	Targets       *NutritionTargets
+	Exercises     []*Exercise
+	Stream        StreamFunc
+	SkipCache     bool
+	Usage         *TokenUsage
+	Validate      func(reply string) error
+	PromptVersion string
+}
+
+// MealPlanInput asks for one dish per slot. Avoid lists dish names already
+// in the plan so regenerated meals add variety. Targets is nil when the
+// profile is too incomplete to compute them. Stream, SkipCache, Usage and
+// Validate work as in RecipeInput.
+type MealPlanInput struct {
+	Goal          string
+	Preferences   string
+	CalorieTarget int
+	Targets       *NutritionTargets
+	Diet          DietaryPreferences
+	Slots         []MealSlotSpec
+	Avoid         []string
+	Stream        StreamFunc
+	SkipCache     bool
+	Usage         *TokenUsage
+	Validate      func(reply string) error
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "context"
+
+// BodyMeasurement is one dated entry in a user's body-metrics history. Zero
+// values mean the metric was not recorded.
+type BodyMeasurement struct {
+	ID         int64   `json:"id"`
+	UserID     int64   `json:"-"`
+	MeasuredAt int64   `json:"measured_at"`
+	WeightKg   float64 `json:"weight_kg,omitempty"`
+	BodyFatPct float64 `json:"body_fat_pct,omitempty"`
+	WaistCm    float64 `json:"waist_cm,omitempty"`
+	ChestCm    float64 `json:"chest_cm,omitempty"`
+	HipsCm     float64 `json:"hips_cm,omitempty"`
+	ArmCm      float64 `json:"arm_cm,omitempty"`
+	ThighCm    float64 `json:"thigh_cm,omitempty"`
+	Notes      string  `json:"notes,omitempty"`
+	CreatedAt  int64   `json:"created_at"`
+}
+
+// WeightTrend summarises recent weight measurements. KgPerWeek is the slope
+// of a least-squares fit, negative when losing weight.
+type WeightTrend struct {
+	Samples   int     `json:"samples"`
+	DaysSpan  int     `json:"days_span"`
+	ChangeKg  float64 `json:"change_kg"`
+	KgPerWeek float64 `json:"kg_per_week"`
+}
+
+type BodyMeasurementRepository interface {
+	// Create also copies the weight to the user's profile when the
+	// measurement is their most recent weight.
+	Create(ctx context.Context, m *BodyMeasurement) error
+	GetLatestWeight(ctx context.Context, userID int64) (*BodyMeasurement, error)
+	GetRange(ctx context.Context, userID int64, from, to int64) ([]*BodyMeasurement, error)
+	Delete(ctx context.Context, id, userID int64) error
+}
+
+type BodyMeasurementService interface {
+	Log(ctx context.Context, userID int64, m *BodyMeasurement) (*BodyMeasurement, error)
+	List(ctx context.Context, userID int64, from, to int64) ([]*BodyMeasurement, *WeightTrend, error)
+	Delete(ctx context.Context, userID, id int64) error
+}
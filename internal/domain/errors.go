@@ -0,0 +1,16 @@
+package domain
+
+import "errors"
+
+// Sentinel errors let handlers choose a status code. Wrap them with context,
+// e.g. fmt.Errorf("workout %w", ErrNotFound).
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrAIUnavailable means the AI provider is down, overloaded or timing
+	// out, so the same request may succeed later.
+	ErrAIUnavailable = errors.New("ai provider unavailable")
+	// ErrQuotaExceeded means the user has used up an AI token quota for
+	// the current day or month.
+	ErrQuotaExceeded = errors.New("ai quota exceeded")
+)
@@ -0,0 +1,46 @@
+package domain
+
+import "context"
+
+const (
+	DifficultyBeginner     = "beginner"
+	DifficultyIntermediate = "intermediate"
+	DifficultyAdvanced     = "advanced"
+)
+
+// Exercise is an entry in the seeded exercise catalog. Plans and workout logs
+// reference it by ID.
+type Exercise struct {
+	ID               int64    `json:"id"`
+	Slug             string   `json:"slug"`
+	Name             string   `json:"name"`
+	PrimaryMuscles   []string `json:"primary_muscles"`
+	SecondaryMuscles []string `json:"secondary_muscles"`
+	Equipment        string   `json:"equipment"`
+	MovementPattern  string   `json:"movement_pattern"`
+	Difficulty       string   `json:"difficulty"`
+	Instructions     string   `json:"instructions"`
+}
+
+// ExerciseFilter narrows a catalog search. Empty fields match everything;
+// Muscle matches primary or secondary muscles.
+type ExerciseFilter struct {
+	Query           string
+	Muscle          string
+	Equipment       string
+	MovementPattern string
+	Difficulty      string
+	Limit           int
+	Offset          int
+}
+
+type ExerciseRepository interface {
+	Search(ctx context.Context, filter *ExerciseFilter) ([]*Exercise, error)
+	GetByID(ctx context.Context, id int64) (*Exercise, error)
+	GetAll(ctx context.Context) ([]*Exercise, error)
+}
+
+type ExerciseService interface {
+	Search(ctx context.Context, filter *ExerciseFilter) ([]*Exercise, error)
+	Get(ctx context.Context, id int64) (*Exercise, error)
+}
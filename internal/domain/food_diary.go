@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	MealBreakfast = "breakfast"
+	MealLunch     = "lunch"
+	MealDinner    = "dinner"
+	MealSnack     = "snack"
+)
+
+// FoodDiaryEntry is something the user ate. Entries logged from a saved
+// recipe keep RecipeID and DishID; either way the nutrition values are
+// totals for the logged servings, copied at logging time so they survive
+// the recipe being deleted.
+type FoodDiaryEntry struct {
+	ID           int64   `json:"id"`
+	UserID       int64   `json:"-"`
+	EatenAt      int64   `json:"eaten_at"`
+	Meal         string  `json:"meal"`
+	RecipeID     int64   `json:"recipe_id,omitempty"`
+	DishID       int64   `json:"dish_id,omitempty"`
+	Name         string  `json:"name"`
+	Servings     float64 `json:"servings"`
+	Calories     int     `json:"calories"`
+	ProteinGrams float64 `json:"protein_g"`
+	CarbsGrams   float64 `json:"carbs_g"`
+	FatGrams     float64 `json:"fat_g"`
+	Notes        string  `json:"notes,omitempty"`
+	CreatedAt    int64   `json:"created_at"`
+}
+
+type MacroTotals struct {
+	Calories     int     `json:"calories"`
+	ProteinGrams float64 `json:"protein_g"`
+	CarbsGrams   float64 `json:"carbs_g"`
+	FatGrams     float64 `json:"fat_g"`
+}
+
+// DiaryDay aggregates one calendar day. Targets and Remaining are nil when
+// the profile is too incomplete to compute targets; Remaining goes negative
+// once a target is exceeded.
+type DiaryDay struct {
+	Date      string            `json:"date"`
+	Entries   []*FoodDiaryEntry `json:"entries,omitempty"`
+	Totals    MacroTotals       `json:"totals"`
+	Targets   *NutritionTargets `json:"targets"`
+	Remaining *MacroTotals      `json:"remaining"`
+}
+
+type FoodDiaryRepository interface {
+	Create(ctx context.Context, entry *FoodDiaryEntry) error
+	GetRange(ctx context.Context, userID int64, from, to int64) ([]*FoodDiaryEntry, error)
+	Delete(ctx context.Context, id, userID int64) error
+}
+
+type FoodDiaryService interface {
+	Log(ctx context.Context, userID int64, entry *FoodDiaryEntry) (*FoodDiaryEntry, error)
+	Day(ctx context.Context, userID int64, date string, loc *time.Location) (*DiaryDay, error)
+	Summary(ctx context.Context, userID int64, from, to string, loc *time.Location) ([]*DiaryDay, error)
+	Delete(ctx context.Context, userID, id int64) error
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	JobKindRecipe       = "recipe"
+	JobKindTrainingPlan = "training_plan"
+	JobKindMealPlan     = "meal_plan"
+)
+
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// ErrJobLost means a worker's lease on a job ran out and another worker
+// claimed it again, so the first worker's outcome is discarded.
+var ErrJobLost = errors.New("job lease lost")
+
+// Job is a queued AI generation. Payload is the request that queued it and
+// ResultID the recipe, training plan or meal plan it produced. A running
+// job whose LockedUntil has passed is treated as abandoned and claimed
+// again, so work survives worker restarts.
+type Job struct {
+	ID          int64
+	UserID      int64
+	Kind        string
+	Status      string
+	Payload     json.RawMessage
+	ResultID    int64
+	Error       string
+	Attempts    int
+	RunAfter    int64
+	LockedUntil int64
+	CreatedAt   int64
+	UpdatedAt   int64
+	FinishedAt  int64
+}
+
+// GenerateRecipeRequest is the payload of a recipe job.
+type GenerateRecipeRequest struct {
+	Ingredients string `json:"ingredients"`
+	NoCache     bool   `json:"no_cache,omitempty"`
+}
+
+type JobRepository interface {
+	Create(ctx context.Context, job *Job) error
+	GetByID(ctx context.Context, id, userID int64) (*Job, error)
+	// Claim locks the oldest runnable job for lease and returns it, or
+	// ErrNotFound when there is none.
+	Claim(ctx context.Context, lease time.Duration) (*Job, error)
+	// Complete, Retry, Fail and Release record the outcome of the claim
+	// that set the job's attempts to attempt. They return ErrJobLost when
+	// the job is no longer running under that claim.
+	Complete(ctx context.Context, id int64, attempt int, resultID int64) error
+	// Retry puts a failed attempt back in the queue to run at runAfter.
+	Retry(ctx context.Context, id int64, attempt int, errMsg string, runAfter int64) error
+	Fail(ctx context.Context, id int64, attempt int, errMsg string) error
+	// Release returns an interrupted job to the queue without counting the
+	// attempt.
+	Release(ctx context.Context, id int64, attempt int) error
+	DeleteFinishedBefore(ctx context.Context, before int64) error
+}
+
+type JobService interface {
+	Enqueue(ctx context.Context, userID int64, kind string, payload any) (*Job, error)
+	Get(ctx context.Context, userID, id int64) (*Job, error)
+}
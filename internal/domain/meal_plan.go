@@ -0,0 +1,57 @@
+package domain
+
+import "context"
+
+// MealPlan is a generated week of meals. CalorieTarget is the daily total
+// the slots were sized against; Preferences is the free-form dietary
+// guidance the user asked for.
+type MealPlan struct {
+	ID            int64
+	UserID        int64
+	CalorieTarget int
+	Preferences   string
+	Slots         []MealSlot
+	CreatedAt     int64
+}
+
+// MealSlot is one meal of one day. Dish nutrition is for a single serving,
+// which is what the plan counts towards the daily target.
+type MealSlot struct {
+	ID        int64  `json:"id"`
+	Day       string `json:"day"`
+	Meal      string `json:"meal"`
+	Dish      Dish   `json:"dish"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// MealSlotSpec asks the provider for one meal of a given size.
+type MealSlotSpec struct {
+	Day      string
+	Meal     string
+	Calories int
+}
+
+type GenerateMealPlanRequest struct {
+	Preferences string `json:"preferences"`
+	// Calories overrides the daily target from the nutrition profile.
+	Calories int `json:"calories"`
+	// NoCache skips the AI response cache.
+	NoCache bool `json:"no_cache,omitempty"`
+}
+
+type MealPlanRepository interface {
+	Create(ctx context.Context, plan *MealPlan) error
+	GetByID(ctx context.Context, id, userID int64) (*MealPlan, error)
+	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*MealPlan, error)
+	Delete(ctx context.Context, id, userID int64) error
+	// UpsertSlot replaces the plan's meal for slot.Day and slot.Meal.
+	UpsertSlot(ctx context.Context, planID int64, slot *MealSlot) error
+}
+
+type MealPlanService interface {
+	Generate(ctx context.Context, userID int64, req *GenerateMealPlanRequest) (*MealPlan, error)
+	List(ctx context.Context, userID int64, limit, offset int) ([]*MealPlan, error)
+	Get(ctx context.Context, userID, id int64) (*MealPlan, error)
+	Delete(ctx context.Context, userID, id int64) error
+	RegenerateSlot(ctx context.Context, userID, id int64, day, meal string) (*MealPlan, error)
+}
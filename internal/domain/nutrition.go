@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// NutritionTargets are the daily energy and macro targets derived from the
+// user's profile. BMR uses the Mifflin-St Jeor equation and TDEE scales it
+// by ActivityLevel.
+type NutritionTargets struct {
+	Goal          string  `json:"goal"`
+	ActivityLevel string  `json:"activity_level"`
+	WeightKg      float64 `json:"weight_kg"`
+	BMR           int     `json:"bmr"`
+	TDEE          int     `json:"tdee"`
+	Calories      int     `json:"calories"`
+	ProteinGrams  int     `json:"protein_g"`
+	CarbsGrams    int     `json:"carbs_g"`
+	FatGrams      int     `json:"fat_g"`
+}
+
+type NutritionService interface {
+	// Targets fails with ErrInvalidInput when the profile lacks the
+	// height, weight, sex or birth year the calculation needs.
+	Targets(ctx context.Context, userID int64) (*NutritionTargets, error)
+}
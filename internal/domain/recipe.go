@@ -2,22 +2,83 @@ package domain
 
 import "context"
 
+// Recipe is one generation request: the ingredients the user supplied, the
+// raw model reply, and the dishes parsed out of it. PromptVersion is the
+// prompt template version it was generated with, empty for the mock
+// provider and recipes that predate versioning.
 type Recipe struct {
-	ID          int64
-	UserID      int64
-	Ingredients string
-	AIResponse  string
-	CreatedAt   int64
+	ID            int64
+	UserID        int64
+	Ingredients   string
+	AIResponse    string
+	PromptVersion string
+	Dishes        []Dish
+	IsFavorite    bool
+	CreatedAt     int64
+}
+
+// Dish is a single structured recipe suggestion. Calories and macros are per
+// serving. Flags note conflicts with the user's dietary restrictions or
+// disliked foods found when the dish was generated.
+type Dish struct {
+	ID              int64            `json:"id"`
+	Name            string           `json:"name"`
+	Servings        int              `json:"servings"`
+	PrepTimeMinutes int              `json:"prep_time_minutes"`
+	Calories        int              `json:"calories"`
+	ProteinGrams    float64          `json:"protein_g"`
+	CarbsGrams      float64          `json:"carbs_g"`
+	FatGrams        float64          `json:"fat_g"`
+	Ingredients     []DishIngredient `json:"ingredients"`
+	Steps           []string         `json:"steps"`
+	Flags           []string         `json:"flags,omitempty"`
+}
+
+type DishIngredient struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+// RecipeSearchFilter is a full-text query over a user's recipes. The
+// calorie and prep-time bounds are per serving and match recipes with at
+// least one dish inside them; 0 means unbounded.
+type RecipeSearchFilter struct {
+	Query          string
+	MinCalories    int
+	MaxCalories    int
+	MaxPrepMinutes int
+	Limit          int
+	Offset         int
+}
+
+// RecipeSearchResult is a matching recipe with its relevance rank and a
+// snippet with the matched terms wrapped in <mark> tags. The rest of the
+// snippet is HTML-escaped.
+type RecipeSearchResult struct {
+	Recipe    *Recipe
+	Rank      float64
+	Highlight string
 }
 
 type RecipeRepository interface {
 	Create(ctx context.Context, recipe *Recipe) error
 	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*Recipe, error)
 	GetByID(ctx context.Context, id, userID int64) (*Recipe, error)
+	Delete(ctx context.Context, id, userID int64) error
+	AddFavorite(ctx context.Context, id, userID int64) error
+	RemoveFavorite(ctx context.Context, id, userID int64) error
+	GetFavorites(ctx context.Context, userID int64, limit, offset int) ([]*Recipe, error)
+	Search(ctx context.Context, userID int64, filter *RecipeSearchFilter) ([]*RecipeSearchResult, error)
 }
 
 type RecipeService interface {
 	GenerateFromText(ctx context.Context, userID int64, ingredients string) (*Recipe, error)
 	GenerateFromImage(ctx context.Context, userID int64, imageData []byte) (*Recipe, error)
 	GetHistory(ctx context.Context, userID int64, limit, offset int) ([]*Recipe, error)
+	Get(ctx context.Context, userID, id int64) (*Recipe, error)
+	Delete(ctx context.Context, userID, id int64) error
+	SetFavorite(ctx context.Context, userID, id int64, favorite bool) (*Recipe, error)
+	GetFavorites(ctx context.Context, userID int64, limit, offset int) ([]*Recipe, error)
+	Search(ctx context.Context, userID int64, filter *RecipeSearchFilter) ([]*RecipeSearchResult, error)
 }
@@ -2,16 +2,25 @@ package domain
 
 import "context"
 
+// RefreshToken is one link in a rotation chain. Every token issued from the
+// same login shares a FamilyID; ParentID points at the token it replaced and
+// RotatedAt is set once the token has been exchanged for a new one.
 type RefreshToken struct {
 	ID        int64
 	UserID    int64
 	Token     string
+	FamilyID  string
+	ParentID  int64
 	ExpiresAt int64
+	RotatedAt int64
 }
 
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, rt *RefreshToken) error
 	GetByToken(ctx context.Context, token string) (*RefreshToken, error)
+	MarkRotated(ctx context.Context, id int64) error
 	DeleteByToken(ctx context.Context, token string) error
+	DeleteByUserID(ctx context.Context, userID int64) error
+	DeleteFamily(ctx context.Context, userID int64, familyID string) error
 	DeleteExpiredTokens(ctx context.Context) error
 }
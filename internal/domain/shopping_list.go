@@ -0,0 +1,60 @@
+package domain
+
+import "context"
+
+const (
+	ShoppingListFormatText     = "text"
+	ShoppingListFormatMarkdown = "markdown"
+)
+
+// ShoppingList is a grocery list built from recipes and meal plans. Items
+// are merged per ingredient and unit, so each name appears once per unit
+// family.
+type ShoppingList struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"-"`
+	Name      string             `json:"name"`
+	Items     []ShoppingListItem `json:"items"`
+	CreatedAt int64              `json:"created_at"`
+}
+
+// ShoppingListItem is an ingredient total in a normalized unit: g for
+// weights, ml for volumes, pcs for counts, or the model's unit when it is
+// not convertible. Quantity 0 means "to taste".
+type ShoppingListItem struct {
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Checked  bool    `json:"checked"`
+}
+
+// ShoppingListRecipe selects a recipe's dish, or all its dishes when
+// DishID is 0.
+type ShoppingListRecipe struct {
+	RecipeID int64 `json:"recipe_id"`
+	DishID   int64 `json:"dish_id"`
+}
+
+type CreateShoppingListRequest struct {
+	Name        string               `json:"name"`
+	Recipes     []ShoppingListRecipe `json:"recipes"`
+	MealPlanIDs []int64              `json:"meal_plan_ids"`
+}
+
+type ShoppingListRepository interface {
+	Create(ctx context.Context, list *ShoppingList) error
+	GetByID(ctx context.Context, id, userID int64) (*ShoppingList, error)
+	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*ShoppingList, error)
+	Delete(ctx context.Context, id, userID int64) error
+	SetItemChecked(ctx context.Context, listID, itemID, userID int64, checked bool) error
+}
+
+type ShoppingListService interface {
+	Create(ctx context.Context, userID int64, req *CreateShoppingListRequest) (*ShoppingList, error)
+	List(ctx context.Context, userID int64, limit, offset int) ([]*ShoppingList, error)
+	Get(ctx context.Context, userID, id int64) (*ShoppingList, error)
+	Delete(ctx context.Context, userID, id int64) error
+	CheckItem(ctx context.Context, userID, listID, itemID int64, checked bool) (*ShoppingList, error)
+	Export(ctx context.Context, userID, id int64, format string) (string, error)
+}
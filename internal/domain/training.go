@@ -2,25 +2,78 @@ package domain
 
 import "context"
 
+const (
+	IntensityLow      = "low"
+	IntensityModerate = "moderate"
+	IntensityHigh     = "high"
+)
+
+// TrainingPlan is a generated plan. Each user has at most one active plan;
+// generating a plan makes it active.
 type TrainingPlan struct {
-	ID        int64
-	UserID    int64
-	PlanJSON  string
-	CreatedAt int64
+	ID            int64
+	UserID        int64
+	PlanJSON      string
+	Plan          *WorkoutPlan
+	PromptVersion string
+	IsActive      bool
+	CreatedAt     int64
+}
+
+// WorkoutPlan is the structured content of a training plan, stored as
+// PlanJSON. Plans generated before the schema existed may not decode into it.
+type WorkoutPlan struct {
+	DurationWeeks int        `json:"duration_weeks"`
+	Weeks         []PlanWeek `json:"weeks"`
+	Nutrition     string     `json:"nutrition,omitempty"`
+	Recovery      string     `json:"recovery,omitempty"`
+}
+
+type PlanWeek struct {
+	Week int       `json:"week"`
+	Days []PlanDay `json:"days"`
+}
+
+type PlanDay struct {
+	Day       string         `json:"day"`
+	Focus     string         `json:"focus"`
+	Exercises []PlanExercise `json:"exercises"`
+}
+
+// PlanExercise is prescribed either as sets x reps or as a duration.
+// ExerciseID refers to the exercise catalog; it is 0 in plans generated
+// before the catalog existed.
+type PlanExercise struct {
+	ExerciseID      int64  `json:"exercise_id,omitempty"`
+	Name            string `json:"name"`
+	Sets            int    `json:"sets,omitempty"`
+	Reps            int    `json:"reps,omitempty"`
+	DurationMinutes int    `json:"duration_minutes,omitempty"`
+	Intensity       string `json:"intensity"`
 }
 
 type TrainingRepository interface {
 	Create(ctx context.Context, plan *TrainingPlan) error
 	GetLatestByUserID(ctx context.Context, userID int64) (*TrainingPlan, error)
+	GetActiveByUserID(ctx context.Context, userID int64) (*TrainingPlan, error)
 	GetByID(ctx context.Context, id, userID int64) (*TrainingPlan, error)
+	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*TrainingPlan, error)
+	SetActive(ctx context.Context, id, userID int64) error
+	Delete(ctx context.Context, id, userID int64) error
 }
 
 type TrainingService interface {
 	GeneratePlan(ctx context.Context, userID int64, req *GeneratePlanRequest) (*TrainingPlan, error)
 	GetLatest(ctx context.Context, userID int64) (*TrainingPlan, error)
+	GetActive(ctx context.Context, userID int64) (*TrainingPlan, error)
+	List(ctx context.Context, userID int64, limit, offset int) ([]*TrainingPlan, error)
+	Get(ctx context.Context, userID, id int64) (*TrainingPlan, error)
+	Activate(ctx context.Context, userID, id int64) (*TrainingPlan, error)
+	Delete(ctx context.Context, userID, id int64) error
 }
 
 type GeneratePlanRequest struct {
-	TargetWeight  int `json:"target_weight"`
-	AvailableDays int `json:"available_days"`
+	TargetWeight  int  `json:"target_weight"`
+	AvailableDays int  `json:"available_days"`
+	NoCache       bool `json:"no_cache,omitempty"`
 }
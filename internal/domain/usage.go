@@ -0,0 +1,91 @@
+package domain
+
+import "context"
+
+// Endpoints AI usage is recorded under.
+const (
+	UsageRecipeText   = "recipes.from_text"
+	UsageRecipeImage  = "recipes.from_image"
+	UsageTrainingPlan = "training.generate"
+	UsageMealPlan     = "meal_plans.generate"
+	UsageMealPlanSlot = "meal_plans.regenerate_slot"
+)
+
+// TokenUsage is what a provider reports a completion cost. Replies served
+// from the cache cost nothing.
+type TokenUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+}
+
+// Add accumulates other into u. A nil u ignores it, so callers that do not
+// track usage can leave it unset.
+func (u *TokenUsage) Add(other TokenUsage) {
+	if u == nil {
+		return
+	}
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+}
+
+func (u TokenUsage) Total() int64 {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// AIUsageRecord is the token usage of one generation.
+type AIUsageRecord struct {
+	ID               int64
+	UserID           int64
+	Endpoint         string
+	PromptTokens     int64
+	CompletionTokens int64
+	CreatedAt        int64
+}
+
+// EndpointUsage totals a user's generations on one endpoint.
+type EndpointUsage struct {
+	Endpoint         string `json:"endpoint"`
+	Requests         int64  `json:"requests"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+	TotalTokens      int64  `json:"total_tokens"`
+}
+
+// UsagePeriod is a user's usage in the current quota window, from Start
+// until ResetsAt. Quota is 0 when the window is unlimited; Remaining is then
+// omitted.
+type UsagePeriod struct {
+	Start            int64            `json:"start"`
+	ResetsAt         int64            `json:"resets_at"`
+	Requests         int64            `json:"requests"`
+	PromptTokens     int64            `json:"prompt_tokens"`
+	CompletionTokens int64            `json:"completion_tokens"`
+	TotalTokens      int64            `json:"total_tokens"`
+	Quota            int64            `json:"quota"`
+	Remaining        *int64           `json:"remaining,omitempty"`
+	Endpoints        []*EndpointUsage `json:"endpoints"`
+}
+
+type UsageSummary struct {
+	Day   *UsagePeriod `json:"day"`
+	Month *UsagePeriod `json:"month"`
+}
+
+type AIUsageRepository interface {
+	Create(ctx context.Context, record *AIUsageRecord) error
+	// Summarize totals the user's usage with from <= created_at < to per
+	// endpoint.
+	Summarize(ctx context.Context, userID int64, from, to int64) ([]*EndpointUsage, error)
+}
+
+// UsageService enforces the AI token quotas and keeps the accounting behind
+// them. CheckQuota returns an error wrapping ErrQuotaExceeded once a quota
+// is used up; it reserves nothing, so parallel generations can overshoot.
+// Record never fails the generation it accounts for, and RecordAttempt
+// records a finished generation, including a failed one that spent tokens.
+type UsageService interface {
+	CheckQuota(ctx context.Context, userID int64) error
+	Record(ctx context.Context, userID int64, endpoint string, usage TokenUsage)
+	RecordAttempt(ctx context.Context, userID int64, endpoint string, usage TokenUsage, err error)
+	Summary(ctx context.Context, userID int64) (*UsageSummary, error)
+}
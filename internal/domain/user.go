@@ -2,14 +2,73 @@ package domain
 
 import "context"
 
+const (
+	GoalCut      = "cut"
+	GoalMaintain = "maintain"
+	GoalBulk     = "bulk"
+)
+
+const (
+	SexMale   = "male"
+	SexFemale = "female"
+)
+
+const (
+	ActivitySedentary  = "sedentary"
+	ActivityLight      = "light"
+	ActivityModerate   = "moderate"
+	ActivityActive     = "active"
+	ActivityVeryActive = "very_active"
+)
+
+// Dietary restrictions understood by recipe validation. Others are
+// rejected when the profile is updated.
+const (
+	DietVegan       = "vegan"
+	DietVegetarian  = "vegetarian"
+	DietPescatarian = "pescatarian"
+	DietGlutenFree  = "gluten_free"
+	DietDairyFree   = "dairy_free"
+	DietHalal       = "halal"
+	DietKosher      = "kosher"
+	DietKeto        = "keto"
+	DietLowCarb     = "low_carb"
+)
+
+// User holds the account and the body profile. Sex, BirthYear and
+// ActivityLevel are optional and only needed for nutrition targets.
 type User struct {
-	ID           int64
-	Email        string
-	PasswordHash string
-	Height       int
-	Weight       int
-	Goal         string
-	CreatedAt    int64
+	ID            int64
+	Email         string
+	PasswordHash  string
+	Height        int
+	Weight        int
+	Goal          string
+	Sex           string
+	BirthYear     int
+	ActivityLevel string
+	Diet          DietaryPreferences
+	CreatedAt     int64
+}
+
+// DietaryPreferences are passed to recipe and meal plan prompts. Dishes
+// containing an allergen are rejected; dishes that conflict with a
+// restriction or a disliked food are kept but flagged.
+type DietaryPreferences struct {
+	Restrictions []string
+	Allergens    []string
+	Dislikes     []string
+}
+
+// IsEmpty reports whether no preferences are set.
+func (d DietaryPreferences) IsEmpty() bool {
+	return len(d.Restrictions) == 0 && len(d.Allergens) == 0 && len(d.Dislikes) == 0
+}
+
+// HasProfile reports whether the body metrics needed for plan and recipe
+// generation have been filled in.
+func (u *User) HasProfile() bool {
+	return u.Height > 0 && u.Weight > 0
 }
 
 type UserRepository interface {
@@ -24,5 +83,28 @@ type AuthService interface {
 	Login(ctx context.Context, email, password string) (*User, error)
 	GenerateTokens(userID int64) (accessToken, refreshToken string, err error)
 	ValidateToken(token string) (userID int64, err error)
-	RefreshAccessToken(refreshToken string) (newAccessToken string, err error)
+	RefreshAccessToken(refreshToken string) (newAccessToken, newRefreshToken string, err error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userID int64) error
+}
+
+type UserService interface {
+	GetProfile(ctx context.Context, userID int64) (*User, error)
+	ReplaceProfile(ctx context.Context, userID int64, req *UpdateProfileRequest) (*User, error)
+	PatchProfile(ctx context.Context, userID int64, req *UpdateProfileRequest) (*User, error)
+}
+
+// UpdateProfileRequest uses pointers so PATCH can tell an omitted field
+// apart from an explicit zero value.
+type UpdateProfileRequest struct {
+	Height        *int    `json:"height"`
+	Weight        *int    `json:"weight"`
+	Goal          *string `json:"goal"`
+	Sex           *string `json:"sex"`
+	BirthYear     *int    `json:"birth_year"`
+	ActivityLevel *string `json:"activity_level"`
+	// Restrictions, Allergens and Dislikes replace the whole list when set.
+	Restrictions *[]string `json:"dietary_restrictions"`
+	Allergens    *[]string `json:"allergens"`
+	Dislikes     *[]string `json:"disliked_foods"`
 }
@@ -0,0 +1,50 @@
+package domain
+
+import "context"
+
+// WorkoutSession is a logged training session. EndedAt is 0 while the
+// session is still in progress.
+type WorkoutSession struct {
+	ID        int64             `json:"id"`
+	UserID    int64             `json:"-"`
+	Name      string            `json:"name"`
+	Notes     string            `json:"notes,omitempty"`
+	StartedAt int64             `json:"started_at"`
+	EndedAt   int64             `json:"ended_at,omitempty"`
+	Exercises []WorkoutExercise `json:"exercises"`
+	CreatedAt int64             `json:"created_at"`
+}
+
+// WorkoutExercise optionally links to the exercise catalog via ExerciseID.
+type WorkoutExercise struct {
+	ID         int64        `json:"id"`
+	ExerciseID int64        `json:"exercise_id,omitempty"`
+	Name       string       `json:"name"`
+	Notes      string       `json:"notes,omitempty"`
+	Sets       []WorkoutSet `json:"sets"`
+}
+
+// WorkoutSet records one set. RPE is 0 when not recorded, otherwise 1-10.
+type WorkoutSet struct {
+	ID          int64   `json:"id"`
+	Reps        int     `json:"reps"`
+	WeightKg    float64 `json:"weight_kg"`
+	RPE         float64 `json:"rpe,omitempty"`
+	RestSeconds int     `json:"rest_seconds,omitempty"`
+}
+
+type WorkoutRepository interface {
+	Create(ctx context.Context, session *WorkoutSession) error
+	GetByID(ctx context.Context, id, userID int64) (*WorkoutSession, error)
+	GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*WorkoutSession, error)
+	Update(ctx context.Context, session *WorkoutSession) error
+	Delete(ctx context.Context, id, userID int64) error
+}
+
+type WorkoutService interface {
+	Create(ctx context.Context, userID int64, session *WorkoutSession) (*WorkoutSession, error)
+	Get(ctx context.Context, userID, id int64) (*WorkoutSession, error)
+	List(ctx context.Context, userID int64, limit, offset int) ([]*WorkoutSession, error)
+	Update(ctx context.Context, userID, id int64, session *WorkoutSession) (*WorkoutSession, error)
+	Delete(ctx context.Context, userID, id int64) error
+}
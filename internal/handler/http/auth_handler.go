@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 
+	"gymapp/internal/middleware"
 	"gymapp/internal/service"
 
 	"github.com/labstack/echo/v4"
@@ -37,11 +38,17 @@ type TokenResponse struct {
 }
 
 type UserResponse struct {
-	ID     int64  `json:"id"`
-	Email  string `json:"email"`
-	Height int    `json:"height,omitempty"`
-	Weight int    `json:"weight,omitempty"`
-	Goal   string `json:"goal,omitempty"`
+	ID            int64    `json:"id"`
+	Email         string   `json:"email"`
+	Height        int      `json:"height,omitempty"`
+	Weight        int      `json:"weight,omitempty"`
+	Goal          string   `json:"goal,omitempty"`
+	Sex           string   `json:"sex,omitempty"`
+	BirthYear     int      `json:"birth_year,omitempty"`
+	ActivityLevel string   `json:"activity_level,omitempty"`
+	Restrictions  []string `json:"dietary_restrictions,omitempty"`
+	Allergens     []string `json:"allergens,omitempty"`
+	Dislikes      []string `json:"disliked_foods,omitempty"`
 }
 
 // Register godoc
@@ -112,12 +119,12 @@ func (h *AuthHandler) Login(c echo.Context) error {
 
 // Refresh godoc
 // @Summary Refresh access token
-// @Description Get a new access token using refresh token
+// @Description Exchange a refresh token for a new token pair. The presented refresh token is invalidated; reusing it revokes the whole session.
 // @ID auth-refresh
 // @Accept json
 // @Produce json
 // @Param request body RefreshRequest true "Refresh request"
-// @Success 200 {object} map[string]interface{} "New access token"
+// @Success 200 {object} TokenResponse "New token pair"
 // @Failure 401 {object} map[string]string "Invalid refresh token"
 // @Router /auth/refresh [post]
 func (h *AuthHandler) Refresh(c echo.Context) error {
@@ -126,21 +133,75 @@ func (h *AuthHandler) Refresh(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
 	}
 
-	accessToken, err := h.authService.RefreshAccessToken(req.RefreshToken)
+	accessToken, refreshToken, err := h.authService.RefreshAccessToken(req.RefreshToken)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusUnauthorized, "invalid refresh token")
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"access_token": accessToken,
-		"expires_in":   900,
+	return c.JSON(http.StatusOK, TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    900,
 	})
 }
 
-func RegisterAuthRoutes(e *echo.Echo, authService *service.AuthService) {
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the presented refresh token and every token rotated from the same login
+// @ID auth-logout
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token to revoke"
+// @Success 204 "Token revoked"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Invalid refresh token"
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var req RefreshRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	if req.RefreshToken == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "refresh_token is required")
+	}
+
+	if err := h.authService.Logout(c.Request().Context(), req.RefreshToken); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid refresh token")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary Logout from all sessions
+// @Description Revoke every refresh token issued to the authenticated user
+// @ID auth-logout-all
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 204 "All tokens revoked"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	if err := h.authService.LogoutAll(c.Request().Context(), userID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to revoke tokens")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func RegisterAuthRoutes(e *echo.Echo, auth echo.MiddlewareFunc, authService *service.AuthService) {
 	handler := NewAuthHandler(authService)
 
 	e.POST("/auth/register", handler.Register)
 	e.POST("/auth/login", handler.Login)
 	e.POST("/auth/refresh", handler.Refresh)
+	e.POST("/auth/logout", handler.Logout)
+	e.POST("/auth/logout-all", handler.LogoutAll, auth)
 }
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"gymapp/internal/domain"
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BodyMeasurementHandler struct {
+	measurementService *service.BodyMeasurementService
+}
+
+func NewBodyMeasurementHandler(measurementService *service.BodyMeasurementService) *BodyMeasurementHandler {
+	return &BodyMeasurementHandler{measurementService: measurementService}
+}
+
+type BodyMeasurementRequest struct {
+	MeasuredAt int64   `json:"measured_at"`
+	WeightKg   float64 `json:"weight_kg"`
+	BodyFatPct float64 `json:"body_fat_pct"`
+	WaistCm    float64 `json:"waist_cm"`
+	ChestCm    float64 `json:"chest_cm"`
+	HipsCm     float64 `json:"hips_cm"`
+	ArmCm      float64 `json:"arm_cm"`
+	ThighCm    float64 `json:"thigh_cm"`
+	Notes      string  `json:"notes"`
+}
+
+type BodyMeasurementListResponse struct {
+	Measurements []*domain.BodyMeasurement `json:"measurements"`
+	Trend        *domain.WeightTrend       `json:"trend"`
+}
+
+// LogMeasurement godoc
+// @Summary Log body measurement
+// @Description Record weight, body fat and circumference measurements. At least one metric is required; measured_at defaults to now.
+// @ID body-measurement-create
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body BodyMeasurementRequest true "Measurement"
+// @Success 201 {object} domain.BodyMeasurement "Measurement logged"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /body-measurements [post]
+func (h *BodyMeasurementHandler) LogMeasurement(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req BodyMeasurementRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	m, err := h.measurementService.Log(c.Request().Context(), userID, &domain.BodyMeasurement{
+		MeasuredAt: req.MeasuredAt,
+		WeightKg:   req.WeightKg,
+		BodyFatPct: req.BodyFatPct,
+		WaistCm:    req.WaistCm,
+		ChestCm:    req.ChestCm,
+		HipsCm:     req.HipsCm,
+		ArmCm:      req.ArmCm,
+		ThighCm:    req.ThighCm,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusCreated, m)
+}
+
+// ListMeasurements godoc
+// @Summary List body measurements
+// @Description Retrieve measurements in a time range (unix seconds, default last 90 days) with the weight trend over that range
+// @ID body-measurement-list
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param from query int false "Range start (unix seconds)"
+// @Param to query int false "Range end (unix seconds)"
+// @Success 200 {object} BodyMeasurementListResponse "Measurements and trend"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /body-measurements [get]
+func (h *BodyMeasurementHandler) ListMeasurements(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var from, to int64
+	if f := c.QueryParam("from"); f != "" {
+		if from, err = strconv.ParseInt(f, 10, 64); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid from")
+		}
+	}
+	if t := c.QueryParam("to"); t != "" {
+		if to, err = strconv.ParseInt(t, 10, 64); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid to")
+		}
+	}
+
+	measurements, trend, err := h.measurementService.List(c.Request().Context(), userID, from, to)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	if measurements == nil {
+		measurements = []*domain.BodyMeasurement{}
+	}
+
+	return c.JSON(http.StatusOK, BodyMeasurementListResponse{
+		Measurements: measurements,
+		Trend:        trend,
+	})
+}
+
+// DeleteMeasurement godoc
+// @Summary Delete body measurement
+// @Description Delete a logged measurement
+// @ID body-measurement-delete
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Measurement ID"
+// @Success 204 "Measurement deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Measurement not found"
+// @Router /body-measurements/{id} [delete]
+func (h *BodyMeasurementHandler) DeleteMeasurement(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.measurementService.Delete(c.Request().Context(), userID, id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func RegisterBodyMeasurementRoutes(e *echo.Echo, auth echo.MiddlewareFunc, measurementService *service.BodyMeasurementService) {
+	handler := NewBodyMeasurementHandler(measurementService)
+
+	g := e.Group("/body-measurements", auth)
+	g.POST("", handler.LogMeasurement)
+	g.GET("", handler.ListMeasurements)
+	g.DELETE("/:id", handler.DeleteMeasurement)
+}
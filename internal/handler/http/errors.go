@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"gymapp/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// serviceError maps a service error to an HTTP error using the domain
+// sentinels, falling back to 500. Only not-found, validation and quota
+// messages reach the client; other errors, which may carry provider replies or
+// database details, are kept as the internal error for the request log.
+func serviceError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidInput):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, domain.ErrAIUnavailable):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "AI provider is temporarily unavailable, try again later").SetInternal(err)
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "internal server error").SetInternal(err)
+	}
+}
+
+// parsePagination reads limit and offset query params, ignoring invalid
+// values.
+func parsePagination(c echo.Context) (limit, offset int) {
+	limit = 20
+
+	if l := c.QueryParam("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	if o := c.QueryParam("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	return limit, offset
+}
+
+// parseID reads a positive int64 path param.
+func parseID(c echo.Context, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid "+name)
+	}
+	return id, nil
+}
+
+// parseIntQuery reads an optional non-negative int query param, returning 0
+// when it is absent.
+func parseIntQuery(c echo.Context, name string) (int, error) {
+	v := c.QueryParam(name)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid "+name)
+	}
+	return n, nil
+}
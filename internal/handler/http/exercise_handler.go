@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+
+	"gymapp/internal/domain"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ExerciseHandler struct {
+	exerciseService *service.ExerciseService
+}
+
+func NewExerciseHandler(exerciseService *service.ExerciseService) *ExerciseHandler {
+	return &ExerciseHandler{exerciseService: exerciseService}
+}
+
+// SearchExercises godoc
+// @Summary Search exercise catalog
+// @Description Search the exercise library by name and filter by muscle, equipment, movement pattern and difficulty
+// @ID exercise-search
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param q query string false "Name contains"
+// @Param muscle query string false "Primary or secondary muscle, e.g. glutes"
+// @Param equipment query string false "Equipment, e.g. barbell, dumbbell, bodyweight"
+// @Param pattern query string false "Movement pattern, e.g. squat, hinge, vertical_pull"
+// @Param difficulty query string false "beginner, intermediate or advanced"
+// @Param limit query int false "Number of records" default(20)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} domain.Exercise "Exercises"
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /exercises [get]
+func (h *ExerciseHandler) SearchExercises(c echo.Context) error {
+	limit, offset := parsePagination(c)
+
+	exercises, err := h.exerciseService.Search(c.Request().Context(), &domain.ExerciseFilter{
+		Query:           c.QueryParam("q"),
+		Muscle:          c.QueryParam("muscle"),
+		Equipment:       c.QueryParam("equipment"),
+		MovementPattern: c.QueryParam("pattern"),
+		Difficulty:      c.QueryParam("difficulty"),
+		Limit:           limit,
+		Offset:          offset,
+	})
+	if err != nil {
+		return serviceError(err)
+	}
+
+	if exercises == nil {
+		exercises = []*domain.Exercise{}
+	}
+
+	return c.JSON(http.StatusOK, exercises)
+}
+
+// GetExercise godoc
+// @Summary Get exercise
+// @Description Retrieve a catalog exercise with its instructions
+// @ID exercise-get
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Exercise ID"
+// @Success 200 {object} domain.Exercise "Exercise"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Exercise not found"
+// @Router /exercises/{id} [get]
+func (h *ExerciseHandler) GetExercise(c echo.Context) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	exercise, err := h.exerciseService.Get(c.Request().Context(), id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, exercise)
+}
+
+func RegisterExerciseRoutes(e *echo.Echo, auth echo.MiddlewareFunc, exerciseService *service.ExerciseService) {
+	handler := NewExerciseHandler(exerciseService)
+
+	g := e.Group("/exercises", auth)
+	g.GET("", handler.SearchExercises)
+	g.GET("/:id", handler.GetExercise)
+}
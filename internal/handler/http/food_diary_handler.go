@@ -0,0 +1,202 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"gymapp/internal/domain"
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type FoodDiaryHandler struct {
+	diaryService *service.FoodDiaryService
+}
+
+func NewFoodDiaryHandler(diaryService *service.FoodDiaryService) *FoodDiaryHandler {
+	return &FoodDiaryHandler{diaryService: diaryService}
+}
+
+// FoodDiaryRequest logs either a saved recipe (recipe_id, optional dish_id
+// and servings) or a free-form food with its own calories and macros.
+type FoodDiaryRequest struct {
+	EatenAt      int64   `json:"eaten_at"`
+	Meal         string  `json:"meal" enums:"breakfast,lunch,dinner,snack"`
+	RecipeID     int64   `json:"recipe_id"`
+	DishID       int64   `json:"dish_id"`
+	Name         string  `json:"name"`
+	Servings     float64 `json:"servings"`
+	Calories     int     `json:"calories"`
+	ProteinGrams float64 `json:"protein_g"`
+	CarbsGrams   float64 `json:"carbs_g"`
+	FatGrams     float64 `json:"fat_g"`
+	Notes        string  `json:"notes"`
+}
+
+// LogEntry godoc
+// @Summary Log food
+// @Description Log a diary entry. With recipe_id, name and nutrition come from the recipe dish (first dish unless dish_id is set) scaled by servings (default 1); otherwise name and calories are required. eaten_at defaults to now.
+// @ID diary-create
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body FoodDiaryRequest true "Diary entry"
+// @Success 201 {object} domain.FoodDiaryEntry "Entry logged"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /diary [post]
+func (h *FoodDiaryHandler) LogEntry(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req FoodDiaryRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	entry, err := h.diaryService.Log(c.Request().Context(), userID, &domain.FoodDiaryEntry{
+		EatenAt:      req.EatenAt,
+		Meal:         req.Meal,
+		RecipeID:     req.RecipeID,
+		DishID:       req.DishID,
+		Name:         req.Name,
+		Servings:     req.Servings,
+		Calories:     req.Calories,
+		ProteinGrams: req.ProteinGrams,
+		CarbsGrams:   req.CarbsGrams,
+		FatGrams:     req.FatGrams,
+		Notes:        req.Notes,
+	})
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusCreated, entry)
+}
+
+// GetDay godoc
+// @Summary Get diary day
+// @Description Retrieve a day's entries with calorie and macro totals compared against the user's nutrition targets
+// @ID diary-day
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param date query string false "Day as YYYY-MM-DD (default today)"
+// @Param tz query string false "IANA time zone, e.g. Europe/Berlin" default(UTC)
+// @Success 200 {object} domain.DiaryDay "Diary day"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /diary [get]
+func (h *FoodDiaryHandler) GetDay(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	loc, err := parseTimeZone(c)
+	if err != nil {
+		return err
+	}
+
+	day, err := h.diaryService.Day(c.Request().Context(), userID, c.QueryParam("date"), loc)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	if day.Entries == nil {
+		day.Entries = []*domain.FoodDiaryEntry{}
+	}
+
+	return c.JSON(http.StatusOK, day)
+}
+
+// GetSummary godoc
+// @Summary Get diary summary
+// @Description Per-day calorie and macro totals compared against the user's targets, between two dates inclusive (default last 7 days, at most 31)
+// @ID diary-summary
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param from query string false "First day as YYYY-MM-DD"
+// @Param to query string false "Last day as YYYY-MM-DD (default today)"
+// @Param tz query string false "IANA time zone, e.g. Europe/Berlin" default(UTC)
+// @Success 200 {array} domain.DiaryDay "Daily totals"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /diary/summary [get]
+func (h *FoodDiaryHandler) GetSummary(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	loc, err := parseTimeZone(c)
+	if err != nil {
+		return err
+	}
+
+	days, err := h.diaryService.Summary(c.Request().Context(), userID, c.QueryParam("from"), c.QueryParam("to"), loc)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, days)
+}
+
+// DeleteEntry godoc
+// @Summary Delete diary entry
+// @Description Delete a food diary entry
+// @ID diary-delete
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Entry ID"
+// @Success 204 "Entry deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Entry not found"
+// @Router /diary/{id} [delete]
+func (h *FoodDiaryHandler) DeleteEntry(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.diaryService.Delete(c.Request().Context(), userID, id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseTimeZone reads the tz query param, defaulting to UTC.
+func parseTimeZone(c echo.Context) (*time.Location, error) {
+	tz := c.QueryParam("tz")
+	if tz == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid tz")
+	}
+	return loc, nil
+}
+
+func RegisterFoodDiaryRoutes(e *echo.Echo, auth echo.MiddlewareFunc, diaryService *service.FoodDiaryService) {
+	handler := NewFoodDiaryHandler(diaryService)
+
+	g := e.Group("/diary", auth)
+	g.POST("", handler.LogEntry)
+	g.GET("", handler.GetDay)
+	g.GET("/summary", handler.GetSummary)
+	g.DELETE("/:id", handler.DeleteEntry)
+}
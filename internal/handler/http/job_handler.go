@@ -0,0 +1,113 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"gymapp/internal/domain"
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// jobResultPaths maps each job kind to where its result can be fetched.
+var jobResultPaths = map[string]string{
+	domain.JobKindRecipe:       "/recipes/%d",
+	domain.JobKindTrainingPlan: "/training/plans/%d",
+	domain.JobKindMealPlan:     "/meal-plans/%d",
+}
+
+type JobHandler struct {
+	jobService *service.JobService
+}
+
+func NewJobHandler(jobService *service.JobService) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+type JobResponse struct {
+	ID         int64  `json:"id"`
+	Kind       string `json:"kind"`
+	Status     string `json:"status"`
+	Attempts   int    `json:"attempts"`
+	Error      string `json:"error,omitempty"`
+	ResultID   int64  `json:"result_id,omitempty"`
+	ResultURL  string `json:"result_url,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+	FinishedAt int64  `json:"finished_at,omitempty"`
+}
+
+// Get godoc
+// @Summary Get generation job
+// @Description Poll a queued AI generation. Status moves from queued to running and ends as succeeded, with result_id and result_url pointing at the created recipe, training plan or meal plan, or failed, with the last error. Failed attempts are retried with backoff before the job is marked failed.
+// @ID job-get
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Job ID"
+// @Success 200 {object} JobResponse "Job"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Job not found"
+// @Router /jobs/{id} [get]
+func (h *JobHandler) Get(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	job, err := h.jobService.Get(c.Request().Context(), userID, id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newJobResponse(job))
+}
+
+// acceptJob queues a generation and answers 202 with the job and its
+// polling URL in the Location header.
+func acceptJob(c echo.Context, jobService *service.JobService, userID int64, kind string, payload any) error {
+	job, err := jobService.Enqueue(c.Request().Context(), userID, kind, payload)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/jobs/%d", job.ID))
+	return c.JSON(http.StatusAccepted, newJobResponse(job))
+}
+
+func newJobResponse(job *domain.Job) JobResponse {
+	resp := JobResponse{
+		ID:         job.ID,
+		Kind:       job.Kind,
+		Status:     job.Status,
+		Attempts:   job.Attempts,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		UpdatedAt:  job.UpdatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+
+	if job.Status == domain.JobStatusSucceeded {
+		resp.ResultID = job.ResultID
+		if path, ok := jobResultPaths[job.Kind]; ok {
+			resp.ResultURL = fmt.Sprintf(path, job.ResultID)
+		}
+	}
+
+	return resp
+}
+
+func RegisterJobRoutes(e *echo.Echo, auth echo.MiddlewareFunc, jobService *service.JobService) {
+	handler := NewJobHandler(jobService)
+
+	g := e.Group("/jobs", auth)
+	g.GET("/:id", handler.Get)
+}
@@ -0,0 +1,289 @@
+package http
+
+import (
+	"math"
+	"net/http"
+
+	"gymapp/internal/domain"
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MealPlanHandler struct {
+	mealPlanService *service.MealPlanService
+	jobService      *service.JobService
+}
+
+func NewMealPlanHandler(mealPlanService *service.MealPlanService, jobService *service.JobService) *MealPlanHandler {
+	return &MealPlanHandler{mealPlanService: mealPlanService, jobService: jobService}
+}
+
+type GenerateMealPlanRequest struct {
+	// Preferences is free-form dietary guidance, e.g. "vegetarian, no mushrooms".
+	Preferences string `json:"preferences"`
+	// Calories overrides the daily target computed from the profile.
+	Calories int `json:"calories"`
+	// NoCache asks for a fresh reply instead of a cached one.
+	NoCache bool `json:"no_cache"`
+}
+
+type MealPlanResponse struct {
+	ID            int64                 `json:"id"`
+	CalorieTarget int                   `json:"calorie_target"`
+	Preferences   string                `json:"preferences"`
+	Days          []MealPlanDayResponse `json:"days"`
+	CreatedAt     int64                 `json:"created_at"`
+}
+
+// MealPlanDayResponse groups a day's meals with their combined nutrition.
+type MealPlanDayResponse struct {
+	Day    string             `json:"day"`
+	Meals  []domain.MealSlot  `json:"meals"`
+	Totals domain.MacroTotals `json:"totals"`
+}
+
+// GeneratePlan godoc
+// @Summary Generate meal plan
+// @Description Queue generation of a 7-day breakfast/lunch/dinner/snack plan sized to the daily calorie target (from the profile unless calories is set) and the given dietary preferences. Poll the returned job (also in the Location header) for the plan.
+// @ID meal-plan-generate
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body GenerateMealPlanRequest true "Meal plan parameters"
+// @Success 202 {object} JobResponse "Generation queued"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 429 {object} map[string]string "AI token quota used up"
+// @Router /meal-plans [post]
+func (h *MealPlanHandler) GeneratePlan(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req GenerateMealPlanRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	payload := &domain.GenerateMealPlanRequest{
+		Preferences: req.Preferences,
+		Calories:    req.Calories,
+		NoCache:     req.NoCache,
+	}
+	if err := h.mealPlanService.ValidateRequest(c.Request().Context(), userID, payload); err != nil {
+		return serviceError(err)
+	}
+
+	return acceptJob(c, h.jobService, userID, domain.JobKindMealPlan, payload)
+}
+
+// StreamPlan godoc
+// @Summary Stream meal plan generation
+// @Description Generate a meal plan like POST /meal-plans but synchronously, relaying the model's reply as Server-Sent Events while it is written. Each "delta" event carries a StreamDelta; the saved plan follows as a MealPlanResponse in a final "done" event, or an "error" event carries a StreamError with the status code and message if generation fails midway.
+// @ID meal-plan-generate-stream
+// @Accept json
+// @Produce text/event-stream
+// @Security Bearer
+// @Param request body GenerateMealPlanRequest true "Meal plan parameters"
+// @Success 200 {object} StreamDelta "Event stream"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 429 {object} map[string]string "AI token quota used up"
+// @Failure 503 {object} map[string]string "AI provider unavailable"
+// @Failure default {object} StreamError "Data of the error event sent when generation fails after streaming started"
+// @Router /meal-plans/stream [post]
+func (h *MealPlanHandler) StreamPlan(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req GenerateMealPlanRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	sse := newSSEWriter(c)
+	plan, err := h.mealPlanService.StreamPlan(c.Request().Context(), userID, &domain.GenerateMealPlanRequest{
+		Preferences: req.Preferences,
+		Calories:    req.Calories,
+		NoCache:     req.NoCache,
+	}, sse.delta)
+	if err != nil {
+		return sse.finish(nil, err)
+	}
+
+	return sse.finish(newMealPlanResponse(plan), nil)
+}
+
+// ListPlans godoc
+// @Summary List meal plans
+// @Description Retrieve the user's meal plans, most recent first
+// @ID meal-plan-list
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Number of records" default(20)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} MealPlanResponse "Meal plans"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /meal-plans [get]
+func (h *MealPlanHandler) ListPlans(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	limit, offset := parsePagination(c)
+
+	plans, err := h.mealPlanService.List(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	resp := make([]MealPlanResponse, len(plans))
+	for i, plan := range plans {
+		resp[i] = newMealPlanResponse(plan)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetPlan godoc
+// @Summary Get meal plan
+// @Description Retrieve a meal plan by ID with per-day totals
+// @ID meal-plan-get
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Meal plan ID"
+// @Success 200 {object} MealPlanResponse "Meal plan"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Meal plan not found"
+// @Router /meal-plans/{id} [get]
+func (h *MealPlanHandler) GetPlan(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.mealPlanService.Get(c.Request().Context(), userID, id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newMealPlanResponse(plan))
+}
+
+// DeletePlan godoc
+// @Summary Delete meal plan
+// @Description Delete a meal plan and its meals
+// @ID meal-plan-delete
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Meal plan ID"
+// @Success 204 "Meal plan deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Meal plan not found"
+// @Router /meal-plans/{id} [delete]
+func (h *MealPlanHandler) DeletePlan(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.mealPlanService.Delete(c.Request().Context(), userID, id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RegenerateSlot godoc
+// @Summary Regenerate meal
+// @Description Replace a single meal of the plan with a new dish of the same size, leaving the rest of the plan unchanged
+// @ID meal-plan-regenerate-slot
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Meal plan ID"
+// @Param day path string true "Day of the week" Enums(monday,tuesday,wednesday,thursday,friday,saturday,sunday)
+// @Param meal path string true "Meal" Enums(breakfast,lunch,dinner,snack)
+// @Success 200 {object} MealPlanResponse "Updated meal plan"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Meal plan not found"
+// @Failure 429 {object} map[string]string "AI token quota used up"
+// @Failure 503 {object} map[string]string "AI provider unavailable"
+// @Router /meal-plans/{id}/slots/{day}/{meal}/regenerate [post]
+func (h *MealPlanHandler) RegenerateSlot(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.mealPlanService.RegenerateSlot(c.Request().Context(), userID, id, c.Param("day"), c.Param("meal"))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newMealPlanResponse(plan))
+}
+
+// newMealPlanResponse groups the plan's slots, already in calendar order,
+// by day.
+func newMealPlanResponse(plan *domain.MealPlan) MealPlanResponse {
+	resp := MealPlanResponse{
+		ID:            plan.ID,
+		CalorieTarget: plan.CalorieTarget,
+		Preferences:   plan.Preferences,
+		Days:          []MealPlanDayResponse{},
+		CreatedAt:     plan.CreatedAt,
+	}
+
+	for _, slot := range plan.Slots {
+		if n := len(resp.Days); n == 0 || resp.Days[n-1].Day != slot.Day {
+			resp.Days = append(resp.Days, MealPlanDayResponse{Day: slot.Day})
+		}
+
+		day := &resp.Days[len(resp.Days)-1]
+		day.Meals = append(day.Meals, slot)
+		day.Totals.Calories += slot.Dish.Calories
+		day.Totals.ProteinGrams = math.Round((day.Totals.ProteinGrams+slot.Dish.ProteinGrams)*10) / 10
+		day.Totals.CarbsGrams = math.Round((day.Totals.CarbsGrams+slot.Dish.CarbsGrams)*10) / 10
+		day.Totals.FatGrams = math.Round((day.Totals.FatGrams+slot.Dish.FatGrams)*10) / 10
+	}
+
+	return resp
+}
+
+func RegisterMealPlanRoutes(e *echo.Echo, auth echo.MiddlewareFunc, mealPlanService *service.MealPlanService, jobService *service.JobService) {
+	handler := NewMealPlanHandler(mealPlanService, jobService)
+
+	g := e.Group("/meal-plans", auth)
+	g.POST("", handler.GeneratePlan)
+	g.POST("/stream", handler.StreamPlan)
+	g.GET("", handler.ListPlans)
+	g.GET("/:id", handler.GetPlan)
+	g.DELETE("/:id", handler.DeletePlan)
+	g.POST("/:id/slots/:day/:meal/regenerate", handler.RegenerateSlot)
+}
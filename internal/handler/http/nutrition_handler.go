@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NutritionHandler struct {
+	nutritionService *service.NutritionService
+}
+
+func NewNutritionHandler(nutritionService *service.NutritionService) *NutritionHandler {
+	return &NutritionHandler{nutritionService: nutritionService}
+}
+
+// GetTargets godoc
+// @Summary Get nutrition targets
+// @Description Compute daily calorie and macro targets from the profile: BMR (Mifflin-St Jeor), TDEE from activity level (sedentary when unset) and goal-adjusted calories and macros. Uses the latest logged weight when available.
+// @ID nutrition-targets
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} domain.NutritionTargets "Nutrition targets"
+// @Failure 400 {object} map[string]string "Profile incomplete"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /nutrition/targets [get]
+func (h *NutritionHandler) GetTargets(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	targets, err := h.nutritionService.Targets(c.Request().Context(), userID)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, targets)
+}
+
+func RegisterNutritionRoutes(e *echo.Echo, auth echo.MiddlewareFunc, nutritionService *service.NutritionService) {
+	handler := NewNutritionHandler(nutritionService)
+
+	g := e.Group("/nutrition", auth)
+	g.GET("/targets", handler.GetTargets)
+}
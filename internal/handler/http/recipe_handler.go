@@ -1,45 +1,63 @@
 package http
 
 import (
+	"io"
 	"net/http"
-	"strconv"
 
+	"gymapp/internal/domain"
 	"gymapp/internal/middleware"
 	"gymapp/internal/service"
 
 	"github.com/labstack/echo/v4"
 )
 
+const maxImageSize = 10 << 20
+
 type RecipeHandler struct {
 	recipeService *service.RecipeService
+	jobService    *service.JobService
 }
 
-func NewRecipeHandler(recipeService *service.RecipeService) *RecipeHandler {
-	return &RecipeHandler{recipeService: recipeService}
+func NewRecipeHandler(recipeService *service.RecipeService, jobService *service.JobService) *RecipeHandler {
+	return &RecipeHandler{recipeService: recipeService, jobService: jobService}
 }
 
 type RecipeRequest struct {
 	Ingredients string `json:"ingredients"`
+	// NoCache asks for a fresh reply instead of a cached one.
+	NoCache bool `json:"no_cache"`
 }
 
 type RecipeResponse struct {
-	ID          int64  `json:"id"`
-	Ingredients string `json:"ingredients"`
-	AIResponse  string `json:"ai_response"`
-	CreatedAt   int64  `json:"created_at"`
+	ID          int64         `json:"id"`
+	Ingredients string        `json:"ingredients"`
+	Dishes      []domain.Dish `json:"dishes"`
+	AIResponse  string        `json:"ai_response"`
+	// PromptVersion is the prompt template version the recipe was
+	// generated with.
+	PromptVersion string `json:"prompt_version,omitempty"`
+	IsFavorite    bool   `json:"is_favorite"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+type RecipeSearchResponse struct {
+	RecipeResponse
+	Rank      float64 `json:"rank"`
+	Highlight string  `json:"highlight"`
 }
 
 // GenerateFromText godoc
 // @Summary Generate recipe from ingredients text
-// @Description Generate a recipe using AI based on ingredient list
+// @Description Queue generation of recipes using AI based on an ingredient list. Poll the returned job (also in the Location header) for the recipe.
 // @ID recipe-generate-text
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param request body RecipeRequest true "Ingredients for recipe"
-// @Success 201 {object} RecipeResponse "Recipe generated"
+// @Success 202 {object} JobResponse "Generation queued"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 429 {object} map[string]string "AI token quota used up"
 // @Router /recipes/from-text [post]
 func (h *RecipeHandler) GenerateFromText(c echo.Context) error {
 	userID, err := middleware.GetUserID(c)
@@ -52,22 +70,55 @@ func (h *RecipeHandler) GenerateFromText(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
 	}
 
-	recipe, err := h.recipeService.GenerateFromText(c.Request().Context(), userID, req.Ingredients)
+	payload := &domain.GenerateRecipeRequest{Ingredients: req.Ingredients, NoCache: req.NoCache}
+	if err := h.recipeService.ValidateTextRequest(c.Request().Context(), userID, payload); err != nil {
+		return serviceError(err)
+	}
+
+	return acceptJob(c, h.jobService, userID, domain.JobKindRecipe, payload)
+}
+
+// StreamFromText godoc
+// @Summary Stream recipe generation from ingredients text
+// @Description Generate recipes like /recipes/from-text but synchronously, relaying the model's reply as Server-Sent Events while it is written. Each "delta" event carries a StreamDelta; the saved recipe follows as a RecipeResponse in a final "done" event, or an "error" event carries a StreamError with the status code and message if generation fails midway.
+// @ID recipe-stream-text
+// @Accept json
+// @Produce text/event-stream
+// @Security Bearer
+// @Param request body RecipeRequest true "Ingredients for recipe"
+// @Success 200 {object} StreamDelta "Event stream"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 429 {object} map[string]string "AI token quota used up"
+// @Failure 503 {object} map[string]string "AI provider unavailable"
+// @Failure default {object} StreamError "Data of the error event sent when generation fails after streaming started"
+// @Router /recipes/from-text/stream [post]
+func (h *RecipeHandler) StreamFromText(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
 	}
 
-	return c.JSON(http.StatusCreated, RecipeResponse{
-		ID:          recipe.ID,
-		Ingredients: recipe.Ingredients,
-		AIResponse:  recipe.AIResponse,
-		CreatedAt:   recipe.CreatedAt,
-	})
+	var req RecipeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	sse := newSSEWriter(c)
+	recipe, err := h.recipeService.StreamFromText(c.Request().Context(), userID, &domain.GenerateRecipeRequest{
+		Ingredients: req.Ingredients,
+		NoCache:     req.NoCache,
+	}, sse.delta)
+	if err != nil {
+		return sse.finish(nil, err)
+	}
+
+	return sse.finish(newRecipeResponse(recipe), nil)
 }
 
 // GenerateFromImage godoc
 // @Summary Generate recipe from image
-// @Description Generate a recipe by uploading a food image
+// @Description Recognize ingredients in an uploaded food image (JPEG, PNG, GIF or WebP, max 10MB) and generate recipes from them
 // @ID recipe-generate-image
 // @Accept multipart/form-data
 // @Produce json
@@ -76,6 +127,9 @@ func (h *RecipeHandler) GenerateFromText(c echo.Context) error {
 // @Success 201 {object} RecipeResponse "Recipe generated"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 413 {object} map[string]string "Image too large"
+// @Failure 429 {object} map[string]string "AI token quota used up"
+// @Failure 503 {object} map[string]string "AI provider unavailable"
 // @Router /recipes/from-image [post]
 func (h *RecipeHandler) GenerateFromImage(c echo.Context) error {
 	userID, err := middleware.GetUserID(c)
@@ -88,28 +142,27 @@ func (h *RecipeHandler) GenerateFromImage(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "image file is required")
 	}
 
+	if file.Size > maxImageSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "image must be at most 10MB")
+	}
+
 	src, err := file.Open()
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "failed to open file")
 	}
 	defer src.Close()
 
-	buf := make([]byte, file.Size)
-	if _, err := src.Read(buf); err != nil {
+	buf, err := io.ReadAll(io.LimitReader(src, maxImageSize))
+	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "failed to read file")
 	}
 
 	recipe, err := h.recipeService.GenerateFromImage(c.Request().Context(), userID, buf)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return serviceError(err)
 	}
 
-	return c.JSON(http.StatusCreated, RecipeResponse{
-		ID:          recipe.ID,
-		Ingredients: recipe.Ingredients,
-		AIResponse:  recipe.AIResponse,
-		CreatedAt:   recipe.CreatedAt,
-	})
+	return c.JSON(http.StatusCreated, newRecipeResponse(recipe))
 }
 
 // GetHistory godoc
@@ -130,44 +183,247 @@ func (h *RecipeHandler) GetHistory(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
 	}
 
-	limit := 20
-	offset := 0
+	limit, offset := parsePagination(c)
 
-	if l := c.QueryParam("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
-			limit = parsed
-		}
+	recipes, err := h.recipeService.GetHistory(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return serviceError(err)
 	}
 
-	if o := c.QueryParam("offset"); o != "" {
-		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
-			offset = parsed
-		}
+	var response []RecipeResponse
+	for _, recipe := range recipes {
+		response = append(response, newRecipeResponse(recipe))
 	}
 
-	recipes, err := h.recipeService.GetHistory(c.Request().Context(), userID, limit, offset)
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetRecipe godoc
+// @Summary Get recipe
+// @Description Retrieve a generated recipe by ID
+// @ID recipe-get
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Recipe ID"
+// @Success 200 {object} RecipeResponse "Recipe"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Recipe not found"
+// @Router /recipes/{id} [get]
+func (h *RecipeHandler) GetRecipe(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
 	}
 
-	var response []RecipeResponse
-	for _, recipe := range recipes {
-		response = append(response, RecipeResponse{
-			ID:          recipe.ID,
-			Ingredients: recipe.Ingredients,
-			AIResponse:  recipe.AIResponse,
-			CreatedAt:   recipe.CreatedAt,
-		})
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	recipe, err := h.recipeService.Get(c.Request().Context(), userID, id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newRecipeResponse(recipe))
+}
+
+// DeleteRecipe godoc
+// @Summary Delete recipe
+// @Description Delete a generated recipe and its dishes
+// @ID recipe-delete
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Recipe ID"
+// @Success 204 "Recipe deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Recipe not found"
+// @Router /recipes/{id} [delete]
+func (h *RecipeHandler) DeleteRecipe(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.recipeService.Delete(c.Request().Context(), userID, id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// FavoriteRecipe godoc
+// @Summary Favorite recipe
+// @Description Add a recipe to the user's favorites
+// @ID recipe-favorite
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Recipe ID"
+// @Success 200 {object} RecipeResponse "Recipe favorited"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Recipe not found"
+// @Router /recipes/{id}/favorite [put]
+func (h *RecipeHandler) FavoriteRecipe(c echo.Context) error {
+	return h.setFavorite(c, true)
+}
+
+// UnfavoriteRecipe godoc
+// @Summary Unfavorite recipe
+// @Description Remove a recipe from the user's favorites
+// @ID recipe-unfavorite
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Recipe ID"
+// @Success 200 {object} RecipeResponse "Recipe unfavorited"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Recipe not found"
+// @Router /recipes/{id}/favorite [delete]
+func (h *RecipeHandler) UnfavoriteRecipe(c echo.Context) error {
+	return h.setFavorite(c, false)
+}
+
+func (h *RecipeHandler) setFavorite(c echo.Context, favorite bool) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	recipe, err := h.recipeService.SetFavorite(c.Request().Context(), userID, id, favorite)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newRecipeResponse(recipe))
+}
+
+// GetFavorites godoc
+// @Summary List favorite recipes
+// @Description Retrieve the user's favorite recipes, most recently favorited first
+// @ID recipe-favorites
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Number of records" default(20)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} RecipeResponse "Favorite recipes"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /recipes/favorites [get]
+func (h *RecipeHandler) GetFavorites(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	limit, offset := parsePagination(c)
+
+	recipes, err := h.recipeService.GetFavorites(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	response := make([]RecipeResponse, len(recipes))
+	for i, recipe := range recipes {
+		response[i] = newRecipeResponse(recipe)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// SearchRecipes godoc
+// @Summary Search recipes
+// @Description Full-text search over the user's recipes (ingredients and generated text), ranked by relevance. Calorie and prep-time filters are per serving and match recipes with at least one dish in range.
+// @ID recipe-search
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param q query string true "Search query (supports quotes, OR and -exclusion)"
+// @Param min_calories query int false "Minimum calories per serving"
+// @Param max_calories query int false "Maximum calories per serving"
+// @Param max_prep_time query int false "Maximum prep time in minutes"
+// @Param limit query int false "Number of records" default(20)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} RecipeSearchResponse "Matching recipes"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /recipes/search [get]
+func (h *RecipeHandler) SearchRecipes(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	filter := &domain.RecipeSearchFilter{Query: c.QueryParam("q")}
+	if filter.MinCalories, err = parseIntQuery(c, "min_calories"); err != nil {
+		return err
+	}
+	if filter.MaxCalories, err = parseIntQuery(c, "max_calories"); err != nil {
+		return err
+	}
+	if filter.MaxPrepMinutes, err = parseIntQuery(c, "max_prep_time"); err != nil {
+		return err
+	}
+	filter.Limit, filter.Offset = parsePagination(c)
+
+	results, err := h.recipeService.Search(c.Request().Context(), userID, filter)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	response := make([]RecipeSearchResponse, len(results))
+	for i, result := range results {
+		response[i] = RecipeSearchResponse{
+			RecipeResponse: newRecipeResponse(result.Recipe),
+			Rank:           result.Rank,
+			Highlight:      result.Highlight,
+		}
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
-func RegisterRecipeRoutes(e *echo.Echo, auth echo.MiddlewareFunc, recipeService *service.RecipeService) {
-	handler := NewRecipeHandler(recipeService)
+func newRecipeResponse(recipe *domain.Recipe) RecipeResponse {
+	dishes := recipe.Dishes
+	if dishes == nil {
+		dishes = []domain.Dish{}
+	}
+
+	return RecipeResponse{
+		ID:            recipe.ID,
+		Ingredients:   recipe.Ingredients,
+		Dishes:        dishes,
+		AIResponse:    recipe.AIResponse,
+		PromptVersion: recipe.PromptVersion,
+		IsFavorite:    recipe.IsFavorite,
+		CreatedAt:     recipe.CreatedAt,
+	}
+}
+
+func RegisterRecipeRoutes(e *echo.Echo, auth echo.MiddlewareFunc, recipeService *service.RecipeService, jobService *service.JobService) {
+	handler := NewRecipeHandler(recipeService, jobService)
 
 	g := e.Group("/recipes", auth)
 	g.POST("/from-text", handler.GenerateFromText)
+	g.POST("/from-text/stream", handler.StreamFromText)
 	g.POST("/from-image", handler.GenerateFromImage)
 	g.GET("/history", handler.GetHistory)
+	g.GET("/favorites", handler.GetFavorites)
+	g.GET("/search", handler.SearchRecipes)
+	g.GET("/:id", handler.GetRecipe)
+	g.DELETE("/:id", handler.DeleteRecipe)
+	g.PUT("/:id/favorite", handler.FavoriteRecipe)
+	g.DELETE("/:id/favorite", handler.UnfavoriteRecipe)
 }
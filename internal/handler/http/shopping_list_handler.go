@@ -0,0 +1,252 @@
+package http
+
+import (
+	"net/http"
+
+	"gymapp/internal/domain"
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ShoppingListHandler struct {
+	listService *service.ShoppingListService
+}
+
+func NewShoppingListHandler(listService *service.ShoppingListService) *ShoppingListHandler {
+	return &ShoppingListHandler{listService: listService}
+}
+
+// CreateShoppingListRequest selects the recipe dishes (all dishes of a
+// recipe when dish_id is omitted) and meal plans to shop for.
+type CreateShoppingListRequest struct {
+	Name        string                      `json:"name"`
+	Recipes     []domain.ShoppingListRecipe `json:"recipes"`
+	MealPlanIDs []int64                     `json:"meal_plan_ids"`
+}
+
+type CheckItemRequest struct {
+	Checked *bool `json:"checked"`
+}
+
+// CreateList godoc
+// @Summary Create shopping list
+// @Description Aggregate the ingredients of the selected recipes and meal plans into a shopping list, normalizing units (g, ml, pcs) and merging duplicates
+// @ID shopping-list-create
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body CreateShoppingListRequest true "Recipes and meal plans to shop for"
+// @Success 201 {object} domain.ShoppingList "Shopping list created"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /shopping-lists [post]
+func (h *ShoppingListHandler) CreateList(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req CreateShoppingListRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	list, err := h.listService.Create(c.Request().Context(), userID, &domain.CreateShoppingListRequest{
+		Name:        req.Name,
+		Recipes:     req.Recipes,
+		MealPlanIDs: req.MealPlanIDs,
+	})
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusCreated, list)
+}
+
+// ListLists godoc
+// @Summary List shopping lists
+// @Description Retrieve the user's shopping lists, most recent first
+// @ID shopping-list-list
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Number of records" default(20)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} domain.ShoppingList "Shopping lists"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /shopping-lists [get]
+func (h *ShoppingListHandler) ListLists(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	limit, offset := parsePagination(c)
+
+	lists, err := h.listService.List(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	if lists == nil {
+		lists = []*domain.ShoppingList{}
+	}
+
+	return c.JSON(http.StatusOK, lists)
+}
+
+// GetList godoc
+// @Summary Get shopping list
+// @Description Retrieve a shopping list by ID
+// @ID shopping-list-get
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Shopping list ID"
+// @Success 200 {object} domain.ShoppingList "Shopping list"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Shopping list not found"
+// @Router /shopping-lists/{id} [get]
+func (h *ShoppingListHandler) GetList(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	list, err := h.listService.Get(c.Request().Context(), userID, id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// DeleteList godoc
+// @Summary Delete shopping list
+// @Description Delete a shopping list and its items
+// @ID shopping-list-delete
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Shopping list ID"
+// @Success 204 "Shopping list deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Shopping list not found"
+// @Router /shopping-lists/{id} [delete]
+func (h *ShoppingListHandler) DeleteList(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.listService.Delete(c.Request().Context(), userID, id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CheckItem godoc
+// @Summary Check off shopping list item
+// @Description Mark a shopping list item as bought or not
+// @ID shopping-list-check-item
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Shopping list ID"
+// @Param item_id path int true "Item ID"
+// @Param request body CheckItemRequest true "Checked state"
+// @Success 200 {object} domain.ShoppingList "Updated shopping list"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Item not found"
+// @Router /shopping-lists/{id}/items/{item_id} [patch]
+func (h *ShoppingListHandler) CheckItem(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	itemID, err := parseID(c, "item_id")
+	if err != nil {
+		return err
+	}
+
+	var req CheckItemRequest
+	if err := c.Bind(&req); err != nil || req.Checked == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "checked is required")
+	}
+
+	list, err := h.listService.CheckItem(c.Request().Context(), userID, id, itemID, *req.Checked)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// ExportList godoc
+// @Summary Export shopping list
+// @Description Render a shopping list as plain text or a Markdown task list
+// @ID shopping-list-export
+// @Accept json
+// @Produce plain,text/markdown
+// @Security Bearer
+// @Param id path int true "Shopping list ID"
+// @Param format query string false "Export format" Enums(text,markdown) default(text)
+// @Success 200 {string} string "Rendered shopping list"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Shopping list not found"
+// @Router /shopping-lists/{id}/export [get]
+func (h *ShoppingListHandler) ExportList(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	format := c.QueryParam("format")
+	text, err := h.listService.Export(c.Request().Context(), userID, id, format)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	if format == domain.ShoppingListFormatMarkdown {
+		return c.Blob(http.StatusOK, "text/markdown; charset=UTF-8", []byte(text))
+	}
+	return c.String(http.StatusOK, text)
+}
+
+func RegisterShoppingListRoutes(e *echo.Echo, auth echo.MiddlewareFunc, listService *service.ShoppingListService) {
+	handler := NewShoppingListHandler(listService)
+
+	g := e.Group("/shopping-lists", auth)
+	g.POST("", handler.CreateList)
+	g.GET("", handler.ListLists)
+	g.GET("/:id", handler.GetList)
+	g.DELETE("/:id", handler.DeleteList)
+	g.PATCH("/:id/items/:item_id", handler.CheckItem)
+	g.GET("/:id/export", handler.ExportList)
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StreamDelta is the data of a "delta" event: the next piece of the model's
+// reply.
+type StreamDelta struct {
+	Text string `json:"text"`
+}
+
+// StreamError is the data of an "error" event, with the status code and
+// message the request would have failed with before streaming started.
+type StreamError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// sseWriter relays a generation to the client as Server-Sent Events. The
+// response is only committed on the first event, so errors that happen
+// before the model starts writing are still returned as plain HTTP errors.
+type sseWriter struct {
+	c       echo.Context
+	started bool
+}
+
+func newSSEWriter(c echo.Context) *sseWriter {
+	return &sseWriter{c: c}
+}
+
+// delta is a domain.StreamFunc that forwards each piece of the reply.
+func (w *sseWriter) delta(text string) error {
+	return w.send("delta", StreamDelta{Text: text})
+}
+
+// finish sends the saved result as the "done" event, or reports err: as an
+// HTTP error when nothing was streamed yet and as an "error" event after.
+// The error is still returned in the latter case so it reaches the request
+// log; Echo does not write it once the response is committed.
+func (w *sseWriter) finish(result any, err error) error {
+	if err == nil {
+		return w.send("done", result)
+	}
+
+	httpErr := serviceError(err)
+	if !w.started {
+		return httpErr
+	}
+
+	if sendErr := w.send("error", StreamError{Status: httpErr.Code, Message: fmt.Sprint(httpErr.Message)}); sendErr != nil {
+		return sendErr
+	}
+	return httpErr
+}
+
+func (w *sseWriter) send(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event, err)
+	}
+
+	res := w.c.Response()
+	if !w.started {
+		w.started = true
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.Header().Set(echo.HeaderConnection, "keep-alive")
+		// Keep reverse proxies such as nginx from buffering the stream.
+		res.Header().Set("X-Accel-Buffering", "no")
+		res.WriteHeader(http.StatusOK)
+	}
+
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	res.Flush()
+
+	return nil
+}
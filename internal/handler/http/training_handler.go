@@ -12,34 +12,44 @@ import (
 
 type TrainingHandler struct {
 	trainingService *service.TrainingService
+	jobService      *service.JobService
 }
 
-func NewTrainingHandler(trainingService *service.TrainingService) *TrainingHandler {
-	return &TrainingHandler{trainingService: trainingService}
+func NewTrainingHandler(trainingService *service.TrainingService, jobService *service.JobService) *TrainingHandler {
+	return &TrainingHandler{trainingService: trainingService, jobService: jobService}
 }
 
 type GeneratePlanRequest struct {
 	TargetWeight  int `json:"target_weight"`
 	AvailableDays int `json:"available_days"`
+	// NoCache asks for a fresh reply instead of a cached one.
+	NoCache bool `json:"no_cache"`
 }
 
 type PlanResponse struct {
-	ID        int64  `json:"id"`
-	PlanJSON  string `json:"plan_json"`
-	CreatedAt int64  `json:"created_at"`
+	ID   int64               `json:"id"`
+	Plan *domain.WorkoutPlan `json:"plan"`
+	// Raw holds the stored text of legacy plans that predate the schema.
+	Raw string `json:"raw,omitempty"`
+	// PromptVersion is the prompt template version the plan was generated
+	// with.
+	PromptVersion string `json:"prompt_version,omitempty"`
+	IsActive      bool   `json:"is_active"`
+	CreatedAt     int64  `json:"created_at"`
 }
 
 // GeneratePlan godoc
 // @Summary Generate training plan
-// @Description Generate a personalized training plan using AI
+// @Description Queue generation of a personalized training plan using AI. Poll the returned job (also in the Location header) for the plan.
 // @ID training-generate
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param request body GeneratePlanRequest true "Training plan parameters"
-// @Success 201 {object} PlanResponse "Training plan generated"
+// @Success 202 {object} JobResponse "Generation queued"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 429 {object} map[string]string "AI token quota used up"
 // @Router /training/generate [post]
 func (h *TrainingHandler) GeneratePlan(c echo.Context) error {
 	userID, err := middleware.GetUserID(c)
@@ -52,19 +62,55 @@ func (h *TrainingHandler) GeneratePlan(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
 	}
 
-	plan, err := h.trainingService.GeneratePlan(c.Request().Context(), userID, &domain.GeneratePlanRequest{
+	payload := &domain.GeneratePlanRequest{
 		TargetWeight:  req.TargetWeight,
 		AvailableDays: req.AvailableDays,
-	})
+		NoCache:       req.NoCache,
+	}
+	if err := h.trainingService.ValidatePlanRequest(c.Request().Context(), userID, payload); err != nil {
+		return serviceError(err)
+	}
+
+	return acceptJob(c, h.jobService, userID, domain.JobKindTrainingPlan, payload)
+}
+
+// StreamPlan godoc
+// @Summary Stream training plan generation
+// @Description Generate a training plan like /training/generate but synchronously, relaying the model's reply as Server-Sent Events while it is written. Each "delta" event carries a StreamDelta; the saved plan follows as a PlanResponse in a final "done" event, or an "error" event carries a StreamError with the status code and message if generation fails midway.
+// @ID training-generate-stream
+// @Accept json
+// @Produce text/event-stream
+// @Security Bearer
+// @Param request body GeneratePlanRequest true "Training plan parameters"
+// @Success 200 {object} StreamDelta "Event stream"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 429 {object} map[string]string "AI token quota used up"
+// @Failure 503 {object} map[string]string "AI provider unavailable"
+// @Failure default {object} StreamError "Data of the error event sent when generation fails after streaming started"
+// @Router /training/generate/stream [post]
+func (h *TrainingHandler) StreamPlan(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
 	}
 
-	return c.JSON(http.StatusCreated, PlanResponse{
-		ID:        plan.ID,
-		PlanJSON:  plan.PlanJSON,
-		CreatedAt: plan.CreatedAt,
-	})
+	var req GeneratePlanRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	sse := newSSEWriter(c)
+	plan, err := h.trainingService.StreamPlan(c.Request().Context(), userID, &domain.GeneratePlanRequest{
+		TargetWeight:  req.TargetWeight,
+		AvailableDays: req.AvailableDays,
+		NoCache:       req.NoCache,
+	}, sse.delta)
+	if err != nil {
+		return sse.finish(nil, err)
+	}
+
+	return sse.finish(newPlanResponse(plan), nil)
 }
 
 // GetLatest godoc
@@ -89,17 +135,183 @@ func (h *TrainingHandler) GetLatest(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "no training plan found")
 	}
 
-	return c.JSON(http.StatusOK, PlanResponse{
-		ID:        plan.ID,
-		PlanJSON:  plan.PlanJSON,
-		CreatedAt: plan.CreatedAt,
-	})
+	return c.JSON(http.StatusOK, newPlanResponse(plan))
+}
+
+// GetActive godoc
+// @Summary Get active training plan
+// @Description Retrieve the plan the user is currently following
+// @ID training-active
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} PlanResponse "Active training plan"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "No active training plan"
+// @Router /training/active [get]
+func (h *TrainingHandler) GetActive(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	plan, err := h.trainingService.GetActive(c.Request().Context(), userID)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newPlanResponse(plan))
+}
+
+// ListPlans godoc
+// @Summary List training plans
+// @Description Retrieve the user's training plan history, most recent first
+// @ID training-plan-list
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Number of records" default(20)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} PlanResponse "Training plans"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /training/plans [get]
+func (h *TrainingHandler) ListPlans(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	limit, offset := parsePagination(c)
+
+	plans, err := h.trainingService.List(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	resp := make([]PlanResponse, len(plans))
+	for i, plan := range plans {
+		resp[i] = newPlanResponse(plan)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetPlan godoc
+// @Summary Get training plan
+// @Description Retrieve a training plan by ID
+// @ID training-plan-get
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Training plan ID"
+// @Success 200 {object} PlanResponse "Training plan"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Training plan not found"
+// @Router /training/plans/{id} [get]
+func (h *TrainingHandler) GetPlan(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.trainingService.Get(c.Request().Context(), userID, id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newPlanResponse(plan))
+}
+
+// ActivatePlan godoc
+// @Summary Activate training plan
+// @Description Make an earlier training plan the active one without regenerating it
+// @ID training-plan-activate
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Training plan ID"
+// @Success 200 {object} PlanResponse "Activated training plan"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Training plan not found"
+// @Router /training/plans/{id}/activate [post]
+func (h *TrainingHandler) ActivatePlan(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.trainingService.Activate(c.Request().Context(), userID, id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newPlanResponse(plan))
+}
+
+// DeletePlan godoc
+// @Summary Delete training plan
+// @Description Delete a training plan. Deleting the active plan activates the most recent remaining one.
+// @ID training-plan-delete
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Training plan ID"
+// @Success 204 "Training plan deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Training plan not found"
+// @Router /training/plans/{id} [delete]
+func (h *TrainingHandler) DeletePlan(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.trainingService.Delete(c.Request().Context(), userID, id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func newPlanResponse(plan *domain.TrainingPlan) PlanResponse {
+	resp := PlanResponse{
+		ID:            plan.ID,
+		Plan:          plan.Plan,
+		PromptVersion: plan.PromptVersion,
+		IsActive:      plan.IsActive,
+		CreatedAt:     plan.CreatedAt,
+	}
+	if plan.Plan == nil {
+		resp.Raw = plan.PlanJSON
+	}
+	return resp
 }
 
-func RegisterTrainingRoutes(e *echo.Echo, auth echo.MiddlewareFunc, trainingService *service.TrainingService) {
-	handler := NewTrainingHandler(trainingService)
+func RegisterTrainingRoutes(e *echo.Echo, auth echo.MiddlewareFunc, trainingService *service.TrainingService, jobService *service.JobService) {
+	handler := NewTrainingHandler(trainingService, jobService)
 
 	g := e.Group("/training", auth)
 	g.POST("/generate", handler.GeneratePlan)
+	g.POST("/generate/stream", handler.StreamPlan)
 	g.GET("/latest", handler.GetLatest)
+	g.GET("/active", handler.GetActive)
+	g.GET("/plans", handler.ListPlans)
+	g.GET("/plans/:id", handler.GetPlan)
+	g.POST("/plans/:id/activate", handler.ActivatePlan)
+	g.DELETE("/plans/:id", handler.DeletePlan)
 }
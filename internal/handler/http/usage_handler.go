@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type UsageHandler struct {
+	usageService *service.UsageService
+}
+
+func NewUsageHandler(usageService *service.UsageService) *UsageHandler {
+	return &UsageHandler{usageService: usageService}
+}
+
+// GetUsage godoc
+// @Summary Get AI usage
+// @Description Tokens spent on AI generations in the current UTC day and month, in total and per endpoint, with the quota and remaining tokens for each period. Once a quota is used up, generation endpoints answer 429 until resets_at. quota is 0 and remaining is omitted when a period is unlimited.
+// @ID user-get-usage
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} domain.UsageSummary "AI usage"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /users/me/usage [get]
+func (h *UsageHandler) GetUsage(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	summary, err := h.usageService.Summary(c.Request().Context(), userID)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
+
+func RegisterUsageRoutes(e *echo.Echo, auth echo.MiddlewareFunc, usageService *service.UsageService) {
+	handler := NewUsageHandler(usageService)
+
+	g := e.Group("/users", auth)
+	g.GET("/me/usage", handler.GetUsage)
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"net/http"
+
+	"gymapp/internal/domain"
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type UserHandler struct {
+	userService *service.UserService
+}
+
+func NewUserHandler(userService *service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+type UpdateProfileRequest struct {
+	Height        *int    `json:"height"`
+	Weight        *int    `json:"weight"`
+	Goal          *string `json:"goal" enums:"cut,maintain,bulk"`
+	Sex           *string `json:"sex" enums:"male,female"`
+	BirthYear     *int    `json:"birth_year"`
+	ActivityLevel *string `json:"activity_level" enums:"sedentary,light,moderate,active,very_active"`
+	// Restrictions accepts vegan, vegetarian, pescatarian, gluten_free,
+	// dairy_free, halal, kosher, keto and low_carb.
+	Restrictions *[]string `json:"dietary_restrictions"`
+	Allergens    *[]string `json:"allergens"`
+	Dislikes     *[]string `json:"disliked_foods"`
+}
+
+// GetProfile godoc
+// @Summary Get current user profile
+// @Description Retrieve the authenticated user's profile
+// @ID user-get-profile
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} UserResponse "User profile"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/me [get]
+func (h *UserHandler) GetProfile(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	user, err := h.userService.GetProfile(c.Request().Context(), userID)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// ReplaceProfile godoc
+// @Summary Replace current user profile
+// @Description Set height, weight and goal, which are required. Sex, birth_year, activity_level and the dietary lists (dietary_restrictions, allergens, disliked_foods) are optional and unchanged when omitted.
+// @ID user-replace-profile
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body UpdateProfileRequest true "Profile fields"
+// @Success 200 {object} UserResponse "Updated profile"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/me [put]
+func (h *UserHandler) ReplaceProfile(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req UpdateProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	user, err := h.userService.ReplaceProfile(c.Request().Context(), userID, req.toDomain())
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// PatchProfile godoc
+// @Summary Update current user profile
+// @Description Update any subset of the profile fields. Dietary lists are replaced as a whole; send an empty list to clear one.
+// @ID user-patch-profile
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body UpdateProfileRequest true "Profile fields"
+// @Success 200 {object} UserResponse "Updated profile"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/me [patch]
+func (h *UserHandler) PatchProfile(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req UpdateProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	user, err := h.userService.PatchProfile(c.Request().Context(), userID, req.toDomain())
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+func (r UpdateProfileRequest) toDomain() *domain.UpdateProfileRequest {
+	return &domain.UpdateProfileRequest{
+		Height:        r.Height,
+		Weight:        r.Weight,
+		Goal:          r.Goal,
+		Sex:           r.Sex,
+		BirthYear:     r.BirthYear,
+		ActivityLevel: r.ActivityLevel,
+		Restrictions:  r.Restrictions,
+		Allergens:     r.Allergens,
+		Dislikes:      r.Dislikes,
+	}
+}
+
+func newUserResponse(user *domain.User) UserResponse {
+	return UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Height:        user.Height,
+		Weight:        user.Weight,
+		Goal:          user.Goal,
+		Sex:           user.Sex,
+		BirthYear:     user.BirthYear,
+		ActivityLevel: user.ActivityLevel,
+		Restrictions:  user.Diet.Restrictions,
+		Allergens:     user.Diet.Allergens,
+		Dislikes:      user.Diet.Dislikes,
+	}
+}
+
+func RegisterUserRoutes(e *echo.Echo, auth echo.MiddlewareFunc, userService *service.UserService) {
+	handler := NewUserHandler(userService)
+
+	g := e.Group("/users", auth)
+	g.GET("/me", handler.GetProfile)
+	g.PUT("/me", handler.ReplaceProfile)
+	g.PATCH("/me", handler.PatchProfile)
+}
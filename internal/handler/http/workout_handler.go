@@ -0,0 +1,240 @@
+package http
+
+import (
+	"net/http"
+
+	"gymapp/internal/domain"
+	"gymapp/internal/middleware"
+	"gymapp/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WorkoutHandler struct {
+	workoutService *service.WorkoutService
+}
+
+func NewWorkoutHandler(workoutService *service.WorkoutService) *WorkoutHandler {
+	return &WorkoutHandler{workoutService: workoutService}
+}
+
+type WorkoutRequest struct {
+	Name      string                   `json:"name"`
+	Notes     string                   `json:"notes"`
+	StartedAt int64                    `json:"started_at"`
+	EndedAt   int64                    `json:"ended_at"`
+	Exercises []WorkoutExerciseRequest `json:"exercises"`
+}
+
+// WorkoutExerciseRequest may reference the exercise catalog; name defaults
+// to the catalog name when exercise_id is set.
+type WorkoutExerciseRequest struct {
+	ExerciseID int64               `json:"exercise_id"`
+	Name       string              `json:"name"`
+	Notes      string              `json:"notes"`
+	Sets       []WorkoutSetRequest `json:"sets"`
+}
+
+type WorkoutSetRequest struct {
+	Reps        int     `json:"reps"`
+	WeightKg    float64 `json:"weight_kg"`
+	RPE         float64 `json:"rpe"`
+	RestSeconds int     `json:"rest_seconds"`
+}
+
+// CreateWorkout godoc
+// @Summary Log a workout
+// @Description Record a workout session with its exercises and sets
+// @ID workout-create
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body WorkoutRequest true "Workout session"
+// @Success 201 {object} domain.WorkoutSession "Workout logged"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /workouts [post]
+func (h *WorkoutHandler) CreateWorkout(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	var req WorkoutRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	session, err := h.workoutService.Create(c.Request().Context(), userID, req.toDomain())
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusCreated, session)
+}
+
+// ListWorkouts godoc
+// @Summary List workouts
+// @Description Retrieve the user's logged workouts, most recent first
+// @ID workout-list
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Number of records" default(20)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {array} domain.WorkoutSession "Workouts"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /workouts [get]
+func (h *WorkoutHandler) ListWorkouts(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	limit, offset := parsePagination(c)
+
+	sessions, err := h.workoutService.List(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	if sessions == nil {
+		sessions = []*domain.WorkoutSession{}
+	}
+
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// GetWorkout godoc
+// @Summary Get workout
+// @Description Retrieve a logged workout by ID
+// @ID workout-get
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Workout ID"
+// @Success 200 {object} domain.WorkoutSession "Workout"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Workout not found"
+// @Router /workouts/{id} [get]
+func (h *WorkoutHandler) GetWorkout(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	session, err := h.workoutService.Get(c.Request().Context(), userID, id)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, session)
+}
+
+// UpdateWorkout godoc
+// @Summary Update workout
+// @Description Replace a logged workout, including all exercises and sets
+// @ID workout-update
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Workout ID"
+// @Param request body WorkoutRequest true "Workout session"
+// @Success 200 {object} domain.WorkoutSession "Workout updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Workout not found"
+// @Router /workouts/{id} [put]
+func (h *WorkoutHandler) UpdateWorkout(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req WorkoutRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request")
+	}
+
+	session, err := h.workoutService.Update(c.Request().Context(), userID, id, req.toDomain())
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, session)
+}
+
+// DeleteWorkout godoc
+// @Summary Delete workout
+// @Description Delete a logged workout
+// @ID workout-delete
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Workout ID"
+// @Success 204 "Workout deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Workout not found"
+// @Router /workouts/{id} [delete]
+func (h *WorkoutHandler) DeleteWorkout(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.workoutService.Delete(c.Request().Context(), userID, id); err != nil {
+		return serviceError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (r WorkoutRequest) toDomain() *domain.WorkoutSession {
+	session := &domain.WorkoutSession{
+		Name:      r.Name,
+		Notes:     r.Notes,
+		StartedAt: r.StartedAt,
+		EndedAt:   r.EndedAt,
+	}
+
+	for _, ex := range r.Exercises {
+		exercise := domain.WorkoutExercise{ExerciseID: ex.ExerciseID, Name: ex.Name, Notes: ex.Notes}
+		for _, set := range ex.Sets {
+			exercise.Sets = append(exercise.Sets, domain.WorkoutSet{
+				Reps:        set.Reps,
+				WeightKg:    set.WeightKg,
+				RPE:         set.RPE,
+				RestSeconds: set.RestSeconds,
+			})
+		}
+		session.Exercises = append(session.Exercises, exercise)
+	}
+
+	return session
+}
+
+func RegisterWorkoutRoutes(e *echo.Echo, auth echo.MiddlewareFunc, workoutService *service.WorkoutService) {
+	handler := NewWorkoutHandler(workoutService)
+
+	g := e.Group("/workouts", auth)
+	g.POST("", handler.CreateWorkout)
+	g.GET("", handler.ListWorkouts)
+	g.GET("/:id", handler.GetWorkout)
+	g.PUT("/:id", handler.UpdateWorkout)
+	g.DELETE("/:id", handler.DeleteWorkout)
+}
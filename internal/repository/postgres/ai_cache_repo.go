@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AICacheRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAICacheRepository(pool *pgxpool.Pool) *AICacheRepository {
+	return &AICacheRepository{pool: pool}
+}
+
+func (r *AICacheRepository) Get(ctx context.Context, key string) (*domain.AICacheEntry, error) {
+	query := `
+		SELECT key, provider, model, reply, created_at, expires_at
+		FROM ai_response_cache
+		WHERE key = $1 AND expires_at > $2
+	`
+
+	entry := &domain.AICacheEntry{}
+	err := r.pool.QueryRow(ctx, query, key, time.Now().Unix()).
+		Scan(&entry.Key, &entry.Provider, &entry.Model, &entry.Reply, &entry.CreatedAt, &entry.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("cache entry %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get cache entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *AICacheRepository) Set(ctx context.Context, entry *domain.AICacheEntry) error {
+	query := `
+		INSERT INTO ai_response_cache (key, provider, model, reply, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (key) DO UPDATE
+		SET reply = EXCLUDED.reply, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		entry.Key, entry.Provider, entry.Model, entry.Reply, entry.CreatedAt, entry.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AICacheRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM ai_response_cache WHERE expires_at <= $1`
+
+	if _, err := r.pool.Exec(ctx, query, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to delete expired cache entries: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AIUsageRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAIUsageRepository(pool *pgxpool.Pool) *AIUsageRepository {
+	return &AIUsageRepository{pool: pool}
+}
+
+func (r *AIUsageRepository) Create(ctx context.Context, record *domain.AIUsageRecord) error {
+	query := `
+		INSERT INTO ai_usage (user_id, endpoint, prompt_tokens, completion_tokens, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(ctx, query, record.UserID, record.Endpoint,
+		record.PromptTokens, record.CompletionTokens, record.CreatedAt).
+		Scan(&record.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to record ai usage: %w", err)
+	}
+
+	return nil
+}
+
+// Summarize returns one row per endpoint used between from and to, most
+// tokens first.
+func (r *AIUsageRepository) Summarize(ctx context.Context, userID int64, from, to int64) ([]*domain.EndpointUsage, error) {
+	query := `
+		SELECT endpoint, COUNT(*), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0)
+		FROM ai_usage
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		GROUP BY endpoint
+		ORDER BY SUM(prompt_tokens + completion_tokens) DESC, endpoint ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ai usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []*domain.EndpointUsage
+	for rows.Next() {
+		u := &domain.EndpointUsage{}
+		if err := rows.Scan(&u.Endpoint, &u.Requests, &u.PromptTokens, &u.CompletionTokens); err != nil {
+			return nil, fmt.Errorf("failed to scan ai usage: %w", err)
+		}
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query ai usage: %w", err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const bodyMeasurementColumns = `
+	id, user_id, measured_at, COALESCE(weight_kg, 0), COALESCE(body_fat_pct, 0),
+	COALESCE(waist_cm, 0), COALESCE(chest_cm, 0), COALESCE(hips_cm, 0),
+	COALESCE(arm_cm, 0), COALESCE(thigh_cm, 0), notes, created_at
+`
+
+type BodyMeasurementRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBodyMeasurementRepository(pool *pgxpool.Pool) *BodyMeasurementRepository {
+	return &BodyMeasurementRepository{pool: pool}
+}
+
+// Create stores a measurement. When it carries the user's most recent
+// weight, the profile weight is updated in the same transaction so the two
+// never disagree.
+func (r *BodyMeasurementRepository) Create(ctx context.Context, m *domain.BodyMeasurement) error {
+	m.CreatedAt = time.Now().Unix()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO body_measurements (user_id, measured_at, weight_kg, body_fat_pct,
+			waist_cm, chest_cm, hips_cm, arm_cm, thigh_cm, notes, created_at)
+		VALUES ($1, $2, NULLIF($3::numeric, 0), NULLIF($4::numeric, 0),
+			NULLIF($5::numeric, 0), NULLIF($6::numeric, 0), NULLIF($7::numeric, 0),
+			NULLIF($8::numeric, 0), NULLIF($9::numeric, 0), $10, $11)
+		RETURNING id
+	`
+
+	err = tx.QueryRow(ctx, query,
+		m.UserID, m.MeasuredAt, m.WeightKg, m.BodyFatPct,
+		m.WaistCm, m.ChestCm, m.HipsCm, m.ArmCm, m.ThighCm, m.Notes, m.CreatedAt).
+		Scan(&m.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create body measurement: %w", err)
+	}
+
+	if m.WeightKg > 0 {
+		syncQuery := `
+			UPDATE users SET weight = ROUND($2::numeric), updated_at = $3
+			WHERE id = $1 AND NOT EXISTS (
+				SELECT 1 FROM body_measurements
+				WHERE user_id = $1 AND weight_kg IS NOT NULL
+					AND (measured_at > $4 OR (measured_at = $4 AND id > $5))
+			)
+		`
+
+		if _, err := tx.Exec(ctx, syncQuery, m.UserID, m.WeightKg, m.CreatedAt, m.MeasuredAt, m.ID); err != nil {
+			return fmt.Errorf("failed to update profile weight: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *BodyMeasurementRepository) GetLatestWeight(ctx context.Context, userID int64) (*domain.BodyMeasurement, error) {
+	query := `
+		SELECT ` + bodyMeasurementColumns + `
+		FROM body_measurements WHERE user_id = $1 AND weight_kg IS NOT NULL
+		ORDER BY measured_at DESC, id DESC
+		LIMIT 1
+	`
+
+	m, err := scanBodyMeasurement(r.pool.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("body measurement %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get body measurement: %w", err)
+	}
+
+	return m, nil
+}
+
+// GetRange returns measurements with from <= measured_at <= to, oldest
+// first. Past 1000 measurements only the newest 1000 are returned.
+func (r *BodyMeasurementRepository) GetRange(ctx context.Context, userID int64, from, to int64) ([]*domain.BodyMeasurement, error) {
+	query := `
+		SELECT ` + bodyMeasurementColumns + `
+		FROM body_measurements
+		WHERE user_id = $1 AND measured_at >= $2 AND measured_at <= $3
+		ORDER BY measured_at DESC, id DESC
+		LIMIT 1000
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query body measurements: %w", err)
+	}
+	defer rows.Close()
+
+	var measurements []*domain.BodyMeasurement
+	for rows.Next() {
+		m, err := scanBodyMeasurement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan body measurement: %w", err)
+		}
+		measurements = append(measurements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read body measurements: %w", err)
+	}
+
+	slices.Reverse(measurements)
+	return measurements, nil
+}
+
+func (r *BodyMeasurementRepository) Delete(ctx context.Context, id, userID int64) error {
+	query := `DELETE FROM body_measurements WHERE id = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete body measurement: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("body measurement %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanBodyMeasurement(row pgx.Row) (*domain.BodyMeasurement, error) {
+	m := &domain.BodyMeasurement{}
+	err := row.Scan(&m.ID, &m.UserID, &m.MeasuredAt, &m.WeightKg, &m.BodyFatPct,
+		&m.WaistCm, &m.ChestCm, &m.HipsCm, &m.ArmCm, &m.ThighCm, &m.Notes, &m.CreatedAt)
+	return m, err
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const exerciseColumns = `
+	id, slug, name, primary_muscles, secondary_muscles, equipment,
+	movement_pattern, difficulty, instructions
+`
+
+type ExerciseRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewExerciseRepository(pool *pgxpool.Pool) *ExerciseRepository {
+	return &ExerciseRepository{pool: pool}
+}
+
+func (r *ExerciseRepository) Search(ctx context.Context, filter *domain.ExerciseFilter) ([]*domain.Exercise, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	query := `
+		SELECT ` + exerciseColumns + `
+		FROM exercises
+		WHERE ($1 = '' OR name ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR $2 = ANY(primary_muscles) OR $2 = ANY(secondary_muscles))
+			AND ($3 = '' OR equipment = $3)
+			AND ($4 = '' OR movement_pattern = $4)
+			AND ($5 = '' OR difficulty = $5)
+		ORDER BY name
+		LIMIT $6 OFFSET $7
+	`
+
+	rows, err := r.pool.Query(ctx, query,
+		filter.Query, filter.Muscle, filter.Equipment, filter.MovementPattern, filter.Difficulty,
+		limit, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exercises: %w", err)
+	}
+
+	return collectExercises(rows)
+}
+
+func (r *ExerciseRepository) GetByID(ctx context.Context, id int64) (*domain.Exercise, error) {
+	query := `SELECT ` + exerciseColumns + ` FROM exercises WHERE id = $1`
+
+	ex, err := scanExercise(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("exercise %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get exercise: %w", err)
+	}
+
+	return ex, nil
+}
+
+// GetAll returns the whole catalog. It is small and seeded by migrations.
+func (r *ExerciseRepository) GetAll(ctx context.Context) ([]*domain.Exercise, error) {
+	query := `SELECT ` + exerciseColumns + ` FROM exercises ORDER BY id`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exercises: %w", err)
+	}
+
+	return collectExercises(rows)
+}
+
+func collectExercises(rows pgx.Rows) ([]*domain.Exercise, error) {
+	defer rows.Close()
+
+	var exercises []*domain.Exercise
+	for rows.Next() {
+		ex, err := scanExercise(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exercise: %w", err)
+		}
+		exercises = append(exercises, ex)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read exercises: %w", err)
+	}
+
+	return exercises, nil
+}
+
+func scanExercise(row pgx.Row) (*domain.Exercise, error) {
+	ex := &domain.Exercise{}
+	err := row.Scan(&ex.ID, &ex.Slug, &ex.Name, &ex.PrimaryMuscles, &ex.SecondaryMuscles,
+		&ex.Equipment, &ex.MovementPattern, &ex.Difficulty, &ex.Instructions)
+	return ex, err
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FoodDiaryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewFoodDiaryRepository(pool *pgxpool.Pool) *FoodDiaryRepository {
+	return &FoodDiaryRepository{pool: pool}
+}
+
+func (r *FoodDiaryRepository) Create(ctx context.Context, entry *domain.FoodDiaryEntry) error {
+	entry.CreatedAt = time.Now().Unix()
+
+	query := `
+		INSERT INTO food_diary_entries (user_id, eaten_at, meal, recipe_id, dish_id, name,
+			servings, calories, protein_g, carbs_g, fat_g, notes, created_at)
+		VALUES ($1, $2, $3, NULLIF($4::bigint, 0), NULLIF($5::bigint, 0), $6,
+			$7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(ctx, query,
+		entry.UserID, entry.EatenAt, entry.Meal, entry.RecipeID, entry.DishID, entry.Name,
+		entry.Servings, entry.Calories, entry.ProteinGrams, entry.CarbsGrams, entry.FatGrams,
+		entry.Notes, entry.CreatedAt).
+		Scan(&entry.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create food diary entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetRange returns entries with from <= eaten_at < to, oldest first. It
+// returns every entry in the range, which the service bounds, so totals are
+// never computed from a truncated list.
+func (r *FoodDiaryRepository) GetRange(ctx context.Context, userID int64, from, to int64) ([]*domain.FoodDiaryEntry, error) {
+	query := `
+		SELECT id, user_id, eaten_at, meal, COALESCE(recipe_id, 0), COALESCE(dish_id, 0), name,
+			servings, calories, protein_g, carbs_g, fat_g, notes, created_at
+		FROM food_diary_entries
+		WHERE user_id = $1 AND eaten_at >= $2 AND eaten_at < $3
+		ORDER BY eaten_at ASC, id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query food diary: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.FoodDiaryEntry
+	for rows.Next() {
+		e := &domain.FoodDiaryEntry{}
+		if err := rows.Scan(&e.ID, &e.UserID, &e.EatenAt, &e.Meal, &e.RecipeID, &e.DishID, &e.Name,
+			&e.Servings, &e.Calories, &e.ProteinGrams, &e.CarbsGrams, &e.FatGrams, &e.Notes, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan food diary entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read food diary: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *FoodDiaryRepository) Delete(ctx context.Context, id, userID int64) error {
+	query := `DELETE FROM food_diary_entries WHERE id = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete food diary entry: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("food diary entry %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const jobColumns = `
+	id, user_id, kind, status, payload, COALESCE(result_id, 0), error, attempts,
+	run_after, locked_until, created_at, updated_at, COALESCE(finished_at, 0)
+`
+
+type JobRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewJobRepository(pool *pgxpool.Pool) *JobRepository {
+	return &JobRepository{pool: pool}
+}
+
+func (r *JobRepository) Create(ctx context.Context, job *domain.Job) error {
+	now := time.Now().Unix()
+	job.Status = domain.JobStatusQueued
+	job.RunAfter = now
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	query := `
+		INSERT INTO generation_jobs (user_id, kind, status, payload, run_after, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(ctx, query,
+		job.UserID, job.Kind, job.Status, job.Payload, job.RunAfter, job.CreatedAt, job.UpdatedAt).
+		Scan(&job.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) GetByID(ctx context.Context, id, userID int64) (*domain.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM generation_jobs WHERE id = $1 AND user_id = $2
+	`
+
+	job, err := scanJob(r.pool.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("job %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Claim takes the oldest queued job that is due, or a running job whose
+// lease expired because its worker died. SKIP LOCKED lets concurrent
+// workers on any replica claim different jobs without waiting on each
+// other.
+func (r *JobRepository) Claim(ctx context.Context, lease time.Duration) (*domain.Job, error) {
+	now := time.Now().Unix()
+
+	query := `
+		UPDATE generation_jobs
+		SET status = $1, attempts = attempts + 1, locked_until = $2, updated_at = $3
+		WHERE id = (
+			SELECT id FROM generation_jobs
+			WHERE (status = $4 AND run_after <= $3) OR (status = $1 AND locked_until < $3)
+			ORDER BY run_after, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.pool.QueryRow(ctx, query,
+		domain.JobStatusRunning, now+int64(lease/time.Second), now, domain.JobStatusQueued))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("job %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Complete marks the job succeeded and drops its payload, which is no
+// longer needed.
+func (r *JobRepository) Complete(ctx context.Context, id int64, attempt int, resultID int64) error {
+	now := time.Now().Unix()
+
+	query := `
+		UPDATE generation_jobs
+		SET status = $2, result_id = $3, error = '', payload = '{}',
+			locked_until = 0, updated_at = $4, finished_at = $4
+		WHERE id = $1 AND status = $5 AND attempts = $6
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, domain.JobStatusSucceeded, resultID, now, domain.JobStatusRunning, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	return claimedRow(result)
+}
+
+func (r *JobRepository) Retry(ctx context.Context, id int64, attempt int, errMsg string, runAfter int64) error {
+	query := `
+		UPDATE generation_jobs
+		SET status = $2, error = $3, run_after = $4, locked_until = 0, updated_at = $5
+		WHERE id = $1 AND status = $6 AND attempts = $7
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, domain.JobStatusQueued, errMsg, runAfter, time.Now().Unix(),
+		domain.JobStatusRunning, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	return claimedRow(result)
+}
+
+func (r *JobRepository) Fail(ctx context.Context, id int64, attempt int, errMsg string) error {
+	now := time.Now().Unix()
+
+	query := `
+		UPDATE generation_jobs
+		SET status = $2, error = $3, payload = '{}', locked_until = 0, updated_at = $4, finished_at = $4
+		WHERE id = $1 AND status = $5 AND attempts = $6
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, domain.JobStatusFailed, errMsg, now, domain.JobStatusRunning, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to fail job: %w", err)
+	}
+
+	return claimedRow(result)
+}
+
+func (r *JobRepository) Release(ctx context.Context, id int64, attempt int) error {
+	query := `
+		UPDATE generation_jobs
+		SET status = $2, attempts = GREATEST(attempts - 1, 0), locked_until = 0, updated_at = $3
+		WHERE id = $1 AND status = $4 AND attempts = $5
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, domain.JobStatusQueued, time.Now().Unix(), domain.JobStatusRunning, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to release job: %w", err)
+	}
+
+	return claimedRow(result)
+}
+
+// claimedRow turns an outcome update that matched no row into ErrJobLost:
+// the lease ran out and another worker claimed the job, bumping attempts.
+func claimedRow(result pgconn.CommandTag) error {
+	if result.RowsAffected() == 0 {
+		return domain.ErrJobLost
+	}
+	return nil
+}
+
+// DeleteFinishedBefore removes succeeded and failed jobs that finished
+// before the given time.
+func (r *JobRepository) DeleteFinishedBefore(ctx context.Context, before int64) error {
+	query := `DELETE FROM generation_jobs WHERE finished_at < $1`
+
+	if _, err := r.pool.Exec(ctx, query, before); err != nil {
+		return fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+
+	return nil
+}
+
+func scanJob(row pgx.Row) (*domain.Job, error) {
+	job := &domain.Job{}
+	err := row.Scan(&job.ID, &job.UserID, &job.Kind, &job.Status, &job.Payload, &job.ResultID, &job.Error,
+		&job.Attempts, &job.RunAfter, &job.LockedUntil, &job.CreatedAt, &job.UpdatedAt, &job.FinishedAt)
+	return job, err
+}
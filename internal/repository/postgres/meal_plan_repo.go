@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const mealPlanColumns = `id, user_id, calorie_target, preferences, created_at`
+
+type MealPlanRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMealPlanRepository(pool *pgxpool.Pool) *MealPlanRepository {
+	return &MealPlanRepository{pool: pool}
+}
+
+func (r *MealPlanRepository) Create(ctx context.Context, plan *domain.MealPlan) error {
+	plan.CreatedAt = time.Now().Unix()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO meal_plans (user_id, calorie_target, preferences, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err = tx.QueryRow(ctx, query, plan.UserID, plan.CalorieTarget, plan.Preferences, plan.CreatedAt).
+		Scan(&plan.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create meal plan: %w", err)
+	}
+
+	for i := range plan.Slots {
+		plan.Slots[i].UpdatedAt = plan.CreatedAt
+		if err := upsertMealSlot(ctx, tx, plan.ID, &plan.Slots[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit meal plan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MealPlanRepository) UpsertSlot(ctx context.Context, planID int64, slot *domain.MealSlot) error {
+	slot.UpdatedAt = time.Now().Unix()
+	return upsertMealSlot(ctx, r.pool, planID, slot)
+}
+
+// rowQuerier is satisfied by both the pool and a transaction.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func upsertMealSlot(ctx context.Context, db rowQuerier, planID int64, slot *domain.MealSlot) error {
+	query := `
+		INSERT INTO meal_plan_slots (plan_id, day, meal, name, servings, prep_time_minutes,
+			calories, protein_g, carbs_g, fat_g, ingredients, steps, flags, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, COALESCE($13::text[], '{}'), $14)
+		ON CONFLICT (plan_id, day, meal) DO UPDATE SET
+			name = EXCLUDED.name,
+			servings = EXCLUDED.servings,
+			prep_time_minutes = EXCLUDED.prep_time_minutes,
+			calories = EXCLUDED.calories,
+			protein_g = EXCLUDED.protein_g,
+			carbs_g = EXCLUDED.carbs_g,
+			fat_g = EXCLUDED.fat_g,
+			ingredients = EXCLUDED.ingredients,
+			steps = EXCLUDED.steps,
+			flags = EXCLUDED.flags,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+
+	dish := &slot.Dish
+	err := db.QueryRow(ctx, query,
+		planID, slot.Day, slot.Meal, dish.Name, dish.Servings, dish.PrepTimeMinutes,
+		dish.Calories, dish.ProteinGrams, dish.CarbsGrams, dish.FatGrams, dish.Ingredients, dish.Steps,
+		dish.Flags, slot.UpdatedAt).
+		Scan(&slot.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to save meal slot: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MealPlanRepository) GetByID(ctx context.Context, id, userID int64) (*domain.MealPlan, error) {
+	query := `
+		SELECT ` + mealPlanColumns + `
+		FROM meal_plans WHERE id = $1 AND user_id = $2
+	`
+
+	plan, err := scanMealPlan(r.pool.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("meal plan %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get meal plan: %w", err)
+	}
+
+	if err := r.loadSlots(ctx, []*domain.MealPlan{plan}); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (r *MealPlanRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.MealPlan, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	query := `
+		SELECT ` + mealPlanColumns + `
+		FROM meal_plans WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query meal plans: %w", err)
+	}
+	defer rows.Close()
+
+	var plans []*domain.MealPlan
+	for rows.Next() {
+		plan, err := scanMealPlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan meal plan: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read meal plans: %w", err)
+	}
+
+	if err := r.loadSlots(ctx, plans); err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+func (r *MealPlanRepository) Delete(ctx context.Context, id, userID int64) error {
+	query := `DELETE FROM meal_plans WHERE id = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete meal plan: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("meal plan %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanMealPlan(row pgx.Row) (*domain.MealPlan, error) {
+	plan := &domain.MealPlan{}
+	err := row.Scan(&plan.ID, &plan.UserID, &plan.CalorieTarget, &plan.Preferences, &plan.CreatedAt)
+	return plan, err
+}
+
+// loadSlots attaches slots to plans with a single query. Slots come back in
+// insertion order; callers that need a calendar order sort them.
+func (r *MealPlanRepository) loadSlots(ctx context.Context, plans []*domain.MealPlan) error {
+	if len(plans) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.MealPlan, len(plans))
+	planIDs := make([]int64, 0, len(plans))
+	for _, plan := range plans {
+		byID[plan.ID] = plan
+		planIDs = append(planIDs, plan.ID)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, plan_id, day, meal, name, servings, prep_time_minutes,
+			calories, protein_g, carbs_g, fat_g, ingredients, steps, flags, updated_at
+		FROM meal_plan_slots WHERE plan_id = ANY($1)
+		ORDER BY plan_id, id
+	`, planIDs)
+	if err != nil {
+		return fmt.Errorf("failed to query meal slots: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var planID int64
+		slot := domain.MealSlot{}
+		dish := &slot.Dish
+		if err := rows.Scan(&slot.ID, &planID, &slot.Day, &slot.Meal, &dish.Name, &dish.Servings,
+			&dish.PrepTimeMinutes, &dish.Calories, &dish.ProteinGrams, &dish.CarbsGrams, &dish.FatGrams,
+			&dish.Ingredients, &dish.Steps, &dish.Flags, &slot.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to scan meal slot: %w", err)
+		}
+
+		plan := byID[planID]
+		plan.Slots = append(plan.Slots, slot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read meal slots: %w", err)
+	}
+
+	return nil
+}
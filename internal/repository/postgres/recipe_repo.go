@@ -2,7 +2,10 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html"
+	"strings"
 	"time"
 
 	"gymapp/internal/domain"
@@ -11,6 +14,27 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const recipeColumns = `
+	recipes.id, recipes.user_id, recipes.ingredients, recipes.ai_response, recipes.prompt_version,
+	EXISTS (
+		SELECT 1 FROM recipe_favorites f
+		WHERE f.recipe_id = recipes.id AND f.user_id = recipes.user_id
+	),
+	recipes.created_at
+`
+
+// Search has ts_headline mark matches with control characters rather than
+// <mark> tags, which are only added once the user-supplied snippet has been
+// HTML-escaped. The characters are stripped from the source text.
+const (
+	highlightStart  = "\x02"
+	highlightStop   = "\x03"
+	headlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop +
+		", MaxFragments=2, MinWords=5, MaxWords=20"
+)
+
+var highlightReplacer = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
 type RecipeRepository struct {
 	pool *pgxpool.Pool
 }
@@ -22,20 +46,66 @@ func NewRecipeRepository(pool *pgxpool.Pool) *RecipeRepository {
 func (r *RecipeRepository) Create(ctx context.Context, recipe *domain.Recipe) error {
 	recipe.CreatedAt = time.Now().Unix()
 
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO recipes (user_id, ingredients, ai_response, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO recipes (user_id, ingredients, ai_response, prompt_version, created_at)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id
 	`
 
-	err := r.pool.QueryRow(ctx, query,
-		recipe.UserID, recipe.Ingredients, recipe.AIResponse, recipe.CreatedAt).
+	err = tx.QueryRow(ctx, query,
+		recipe.UserID, recipe.Ingredients, recipe.AIResponse, recipe.PromptVersion, recipe.CreatedAt).
 		Scan(&recipe.ID)
 
 	if err != nil {
 		return fmt.Errorf("failed to create recipe: %w", err)
 	}
 
+	for i := range recipe.Dishes {
+		if err := createDish(ctx, tx, recipe.ID, i, &recipe.Dishes[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit recipe: %w", err)
+	}
+
+	return nil
+}
+
+func createDish(ctx context.Context, tx pgx.Tx, recipeID int64, position int, dish *domain.Dish) error {
+	query := `
+		INSERT INTO recipe_dishes (recipe_id, position, name, servings, prep_time_minutes,
+			calories, protein_g, carbs_g, fat_g, steps, flags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11::text[], '{}'))
+		RETURNING id
+	`
+
+	err := tx.QueryRow(ctx, query,
+		recipeID, position, dish.Name, dish.Servings, dish.PrepTimeMinutes,
+		dish.Calories, dish.ProteinGrams, dish.CarbsGrams, dish.FatGrams, dish.Steps, dish.Flags).
+		Scan(&dish.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create dish: %w", err)
+	}
+
+	for i, ing := range dish.Ingredients {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO recipe_dish_ingredients (dish_id, position, name, quantity, unit)
+			VALUES ($1, $2, $3, $4, $5)
+		`, dish.ID, i, ing.Name, ing.Quantity, ing.Unit)
+		if err != nil {
+			return fmt.Errorf("failed to create dish ingredient: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -45,46 +115,283 @@ func (r *RecipeRepository) GetByUserID(ctx context.Context, userID int64, limit,
 	}
 
 	query := `
-		SELECT id, user_id, ingredients, ai_response, created_at
+		SELECT ` + recipeColumns + `
 		FROM recipes WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	return r.queryRecipes(ctx, query, userID, limit, offset)
+}
+
+// GetFavorites lists the user's favorite recipes, most recently favorited
+// first.
+func (r *RecipeRepository) GetFavorites(ctx context.Context, userID int64, limit, offset int) ([]*domain.Recipe, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	query := `
+		SELECT ` + recipeColumns + `
+		FROM recipes
+		JOIN recipe_favorites fav ON fav.recipe_id = recipes.id AND fav.user_id = recipes.user_id
+		WHERE recipes.user_id = $1
+		ORDER BY fav.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryRecipes(ctx, query, userID, limit, offset)
+}
+
+func (r *RecipeRepository) GetByID(ctx context.Context, id, userID int64) (*domain.Recipe, error) {
+	query := `
+		SELECT ` + recipeColumns + `
+		FROM recipes WHERE id = $1 AND user_id = $2
+	`
+
+	recipe, err := scanRecipe(r.pool.QueryRow(ctx, query, id, userID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to query recipes: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("recipe %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+
+	if err := r.loadDishes(ctx, []*domain.Recipe{recipe}); err != nil {
+		return nil, err
+	}
+
+	return recipe, nil
+}
+
+func (r *RecipeRepository) Delete(ctx context.Context, id, userID int64) error {
+	query := `DELETE FROM recipes WHERE id = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recipe: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("recipe %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// AddFavorite is idempotent. Callers check that the recipe exists; the
+// insert only matches recipes owned by the user.
+func (r *RecipeRepository) AddFavorite(ctx context.Context, id, userID int64) error {
+	query := `
+		INSERT INTO recipe_favorites (user_id, recipe_id, created_at)
+		SELECT user_id, id, $3 FROM recipes WHERE id = $1 AND user_id = $2
+		ON CONFLICT (user_id, recipe_id) DO NOTHING
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, userID, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to add favorite: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecipeRepository) RemoveFavorite(ctx context.Context, id, userID int64) error {
+	query := `DELETE FROM recipe_favorites WHERE recipe_id = $1 AND user_id = $2`
+
+	if _, err := r.pool.Exec(ctx, query, id, userID); err != nil {
+		return fmt.Errorf("failed to remove favorite: %w", err)
+	}
+
+	return nil
+}
+
+// Search ranks the user's recipes against a websearch-style query over the
+// ingredients and the model reply. The highlight is built from the
+// ingredients, dish names and steps rather than the raw JSON reply.
+func (r *RecipeRepository) Search(ctx context.Context, userID int64, filter *domain.RecipeSearchFilter) ([]*domain.RecipeSearchResult, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	query := `
+		SELECT ` + recipeColumns + `,
+			ts_rank_cd(recipes.search_vector, q.query) AS rank,
+			ts_headline('english', translate(recipes.ingredients || ' ' || COALESCE(doc.text, ''), $8, ''),
+				q.query, $9)
+		FROM recipes
+		CROSS JOIN websearch_to_tsquery('english', $2) AS q(query)
+		LEFT JOIN LATERAL (
+			SELECT string_agg(d.name || '. ' || array_to_string(d.steps, ' '), ' ' ORDER BY d.position) AS text
+			FROM recipe_dishes d WHERE d.recipe_id = recipes.id
+		) doc ON TRUE
+		WHERE recipes.user_id = $1
+			AND recipes.search_vector @@ q.query
+			AND (
+				($3::int = 0 AND $4::int = 0 AND $5::int = 0) OR EXISTS (
+					SELECT 1 FROM recipe_dishes d
+					WHERE d.recipe_id = recipes.id
+						AND ($3::int = 0 OR d.calories >= $3)
+						AND ($4::int = 0 OR d.calories <= $4)
+						AND ($5::int = 0 OR d.prep_time_minutes <= $5)
+				)
+			)
+		ORDER BY rank DESC, recipes.created_at DESC
+		LIMIT $6 OFFSET $7
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, filter.Query,
+		filter.MinCalories, filter.MaxCalories, filter.MaxPrepMinutes, limit, filter.Offset,
+		highlightStart+highlightStop, headlineOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search recipes: %w", err)
 	}
 	defer rows.Close()
 
+	var results []*domain.RecipeSearchResult
 	var recipes []*domain.Recipe
 	for rows.Next() {
 		recipe := &domain.Recipe{}
-		if err := rows.Scan(&recipe.ID, &recipe.UserID, &recipe.Ingredients, &recipe.AIResponse, &recipe.CreatedAt); err != nil {
+		result := &domain.RecipeSearchResult{Recipe: recipe}
+		if err := rows.Scan(&recipe.ID, &recipe.UserID, &recipe.Ingredients, &recipe.AIResponse, &recipe.PromptVersion,
+			&recipe.IsFavorite, &recipe.CreatedAt, &result.Rank, &result.Highlight); err != nil {
 			return nil, fmt.Errorf("failed to scan recipe: %w", err)
 		}
+		result.Highlight = renderHighlight(result.Highlight)
+		results = append(results, result)
 		recipes = append(recipes, recipe)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search recipes: %w", err)
+	}
+	rows.Close()
 
-	return recipes, nil
+	if err := r.loadDishes(ctx, recipes); err != nil {
+		return nil, err
+	}
+
+	return results, nil
 }
 
-func (r *RecipeRepository) GetByID(ctx context.Context, id, userID int64) (*domain.Recipe, error) {
-	query := `
-		SELECT id, user_id, ingredients, ai_response, created_at
-		FROM recipes WHERE id = $1 AND user_id = $2
-	`
+// renderHighlight HTML-escapes a ts_headline snippet and turns the match
+// markers into <mark> tags.
+func renderHighlight(snippet string) string {
+	return highlightReplacer.Replace(html.EscapeString(snippet))
+}
 
+func (r *RecipeRepository) queryRecipes(ctx context.Context, query string, args ...any) ([]*domain.Recipe, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recipes: %w", err)
+	}
+	defer rows.Close()
+
+	var recipes []*domain.Recipe
+	for rows.Next() {
+		recipe, err := scanRecipe(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recipe: %w", err)
+		}
+		recipes = append(recipes, recipe)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recipes: %w", err)
+	}
+	rows.Close()
+
+	if err := r.loadDishes(ctx, recipes); err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+func scanRecipe(row pgx.Row) (*domain.Recipe, error) {
 	recipe := &domain.Recipe{}
-	err := r.pool.QueryRow(ctx, query, id, userID).Scan(
-		&recipe.ID, &recipe.UserID, &recipe.Ingredients, &recipe.AIResponse, &recipe.CreatedAt)
+	err := row.Scan(&recipe.ID, &recipe.UserID, &recipe.Ingredients, &recipe.AIResponse, &recipe.PromptVersion,
+		&recipe.IsFavorite, &recipe.CreatedAt)
+	return recipe, err
+}
+
+// loadDishes attaches dishes and their ingredients to recipes using one query
+// per table rather than one per recipe.
+func (r *RecipeRepository) loadDishes(ctx context.Context, recipes []*domain.Recipe) error {
+	if len(recipes) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.Recipe, len(recipes))
+	recipeIDs := make([]int64, 0, len(recipes))
+	for _, recipe := range recipes {
+		byID[recipe.ID] = recipe
+		recipeIDs = append(recipeIDs, recipe.ID)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, recipe_id, name, servings, prep_time_minutes,
+			calories, protein_g, carbs_g, fat_g, steps, flags
+		FROM recipe_dishes WHERE recipe_id = ANY($1)
+		ORDER BY recipe_id, position
+	`, recipeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to query dishes: %w", err)
+	}
+	defer rows.Close()
+
+	type dishRef struct {
+		recipe *domain.Recipe
+		index  int
+	}
+	dishes := make(map[int64]dishRef)
+	var dishIDs []int64
 
+	for rows.Next() {
+		var recipeID int64
+		dish := domain.Dish{}
+		if err := rows.Scan(&dish.ID, &recipeID, &dish.Name, &dish.Servings, &dish.PrepTimeMinutes,
+			&dish.Calories, &dish.ProteinGrams, &dish.CarbsGrams, &dish.FatGrams, &dish.Steps,
+			&dish.Flags); err != nil {
+			return fmt.Errorf("failed to scan dish: %w", err)
+		}
+
+		recipe := byID[recipeID]
+		recipe.Dishes = append(recipe.Dishes, dish)
+		dishes[dish.ID] = dishRef{recipe: recipe, index: len(recipe.Dishes) - 1}
+		dishIDs = append(dishIDs, dish.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read dishes: %w", err)
+	}
+	rows.Close()
+
+	if len(dishIDs) == 0 {
+		return nil
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT dish_id, name, quantity, unit
+		FROM recipe_dish_ingredients WHERE dish_id = ANY($1)
+		ORDER BY dish_id, position
+	`, dishIDs)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("recipe not found")
+		return fmt.Errorf("failed to query dish ingredients: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dishID int64
+		ing := domain.DishIngredient{}
+		if err := rows.Scan(&dishID, &ing.Name, &ing.Quantity, &ing.Unit); err != nil {
+			return fmt.Errorf("failed to scan dish ingredient: %w", err)
 		}
-		return nil, fmt.Errorf("failed to get recipe: %w", err)
+
+		ref := dishes[dishID]
+		dish := &ref.recipe.Dishes[ref.index]
+		dish.Ingredients = append(dish.Ingredients, ing)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read dish ingredients: %w", err)
 	}
 
-	return recipe, nil
+	return nil
 }
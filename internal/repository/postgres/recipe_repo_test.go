@@ -0,0 +1,20 @@
+package postgres
+
+import "testing"
+
+func TestRenderHighlight(t *testing.T) {
+	tests := []struct {
+		snippet string
+		want    string
+	}{
+		{"\x02eggs\x03 and spinach", "<mark>eggs</mark> and spinach"},
+		{"<img src=x onerror=\"alert(1)\"> \x02eggs\x03", "&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>eggs</mark>"},
+		{"salt & \x02pepper\x03 <b>", "salt &amp; <mark>pepper</mark> &lt;b&gt;"},
+	}
+
+	for _, tt := range tests {
+		if got := renderHighlight(tt.snippet); got != tt.want {
+			t.Errorf("renderHighlight(%q) = %q, want %q", tt.snippet, got, tt.want)
+		}
+	}
+}
@@ -22,14 +22,15 @@ func NewRefreshTokenRepository(pool *pgxpool.Pool) *RefreshTokenRepository {
 
 func (r *RefreshTokenRepository) Create(ctx context.Context, rt *domain.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (user_id, token, expires_at, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO refresh_tokens (user_id, token, family_id, parent_id, expires_at, created_at)
+		VALUES ($1, $2, $3, NULLIF($4::bigint, 0), $5, $6)
 		RETURNING id
 	`
 
 	rt.ExpiresAt = time.Now().AddDate(0, 0, 7).Unix()
 
-	err := r.pool.QueryRow(ctx, query, rt.UserID, rt.Token, rt.ExpiresAt, time.Now().Unix()).
+	err := r.pool.QueryRow(ctx, query,
+		rt.UserID, rt.Token, rt.FamilyID, rt.ParentID, rt.ExpiresAt, time.Now().Unix()).
 		Scan(&rt.ID)
 
 	if err != nil {
@@ -41,13 +42,13 @@ func (r *RefreshTokenRepository) Create(ctx context.Context, rt *domain.RefreshT
 
 func (r *RefreshTokenRepository) GetByToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token, expires_at
+		SELECT id, user_id, token, family_id, COALESCE(parent_id, 0), expires_at, COALESCE(rotated_at, 0)
 		FROM refresh_tokens WHERE token = $1
 	`
 
 	rt := &domain.RefreshToken{}
 	err := r.pool.QueryRow(ctx, query, token).Scan(
-		&rt.ID, &rt.UserID, &rt.Token, &rt.ExpiresAt)
+		&rt.ID, &rt.UserID, &rt.Token, &rt.FamilyID, &rt.ParentID, &rt.ExpiresAt, &rt.RotatedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -59,6 +60,23 @@ func (r *RefreshTokenRepository) GetByToken(ctx context.Context, token string) (
 	return rt, nil
 }
 
+// MarkRotated flags a token as exchanged. It only succeeds once per token, so
+// two concurrent refreshes with the same token cannot both win.
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, id int64) error {
+	query := `UPDATE refresh_tokens SET rotated_at = $1 WHERE id = $2 AND rotated_at IS NULL`
+
+	result, err := r.pool.Exec(ctx, query, time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("token already rotated")
+	}
+
+	return nil
+}
+
 func (r *RefreshTokenRepository) DeleteByToken(ctx context.Context, token string) error {
 	query := `DELETE FROM refresh_tokens WHERE token = $1`
 
@@ -74,6 +92,28 @@ func (r *RefreshTokenRepository) DeleteByToken(ctx context.Context, token string
 	return nil
 }
 
+func (r *RefreshTokenRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	query := `DELETE FROM refresh_tokens WHERE user_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) DeleteFamily(ctx context.Context, userID int64, familyID string) error {
+	query := `DELETE FROM refresh_tokens WHERE user_id = $1 AND family_id = $2`
+
+	_, err := r.pool.Exec(ctx, query, userID, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete refresh token family: %w", err)
+	}
+
+	return nil
+}
+
 func (r *RefreshTokenRepository) DeleteExpiredTokens(ctx context.Context) error {
 	query := `DELETE FROM refresh_tokens WHERE expires_at < $1`
 
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ShoppingListRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewShoppingListRepository(pool *pgxpool.Pool) *ShoppingListRepository {
+	return &ShoppingListRepository{pool: pool}
+}
+
+func (r *ShoppingListRepository) Create(ctx context.Context, list *domain.ShoppingList) error {
+	list.CreatedAt = time.Now().Unix()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO shopping_lists (user_id, name, created_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	err = tx.QueryRow(ctx, query, list.UserID, list.Name, list.CreatedAt).Scan(&list.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create shopping list: %w", err)
+	}
+
+	for i := range list.Items {
+		item := &list.Items[i]
+		err := tx.QueryRow(ctx, `
+			INSERT INTO shopping_list_items (list_id, position, name, quantity, unit, checked)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, list.ID, i, item.Name, item.Quantity, item.Unit, item.Checked).Scan(&item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create shopping list item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit shopping list: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ShoppingListRepository) GetByID(ctx context.Context, id, userID int64) (*domain.ShoppingList, error) {
+	query := `
+		SELECT id, user_id, name, created_at
+		FROM shopping_lists WHERE id = $1 AND user_id = $2
+	`
+
+	list, err := scanShoppingList(r.pool.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("shopping list %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get shopping list: %w", err)
+	}
+
+	if err := r.loadItems(ctx, []*domain.ShoppingList{list}); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r *ShoppingListRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.ShoppingList, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	query := `
+		SELECT id, user_id, name, created_at
+		FROM shopping_lists WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shopping lists: %w", err)
+	}
+	defer rows.Close()
+
+	var lists []*domain.ShoppingList
+	for rows.Next() {
+		list, err := scanShoppingList(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan shopping list: %w", err)
+		}
+		lists = append(lists, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read shopping lists: %w", err)
+	}
+	rows.Close()
+
+	if err := r.loadItems(ctx, lists); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (r *ShoppingListRepository) Delete(ctx context.Context, id, userID int64) error {
+	query := `DELETE FROM shopping_lists WHERE id = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete shopping list: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("shopping list %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *ShoppingListRepository) SetItemChecked(ctx context.Context, listID, itemID, userID int64, checked bool) error {
+	query := `
+		UPDATE shopping_list_items i SET checked = $4
+		FROM shopping_lists l
+		WHERE i.id = $1 AND i.list_id = $2 AND l.id = i.list_id AND l.user_id = $3
+	`
+
+	result, err := r.pool.Exec(ctx, query, itemID, listID, userID, checked)
+	if err != nil {
+		return fmt.Errorf("failed to update shopping list item: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("shopping list item %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanShoppingList(row pgx.Row) (*domain.ShoppingList, error) {
+	list := &domain.ShoppingList{}
+	err := row.Scan(&list.ID, &list.UserID, &list.Name, &list.CreatedAt)
+	return list, err
+}
+
+// loadItems attaches items to lists with a single query.
+func (r *ShoppingListRepository) loadItems(ctx context.Context, lists []*domain.ShoppingList) error {
+	if len(lists) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.ShoppingList, len(lists))
+	listIDs := make([]int64, 0, len(lists))
+	for _, list := range lists {
+		list.Items = []domain.ShoppingListItem{}
+		byID[list.ID] = list
+		listIDs = append(listIDs, list.ID)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, list_id, name, quantity, unit, checked
+		FROM shopping_list_items WHERE list_id = ANY($1)
+		ORDER BY list_id, position
+	`, listIDs)
+	if err != nil {
+		return fmt.Errorf("failed to query shopping list items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var listID int64
+		item := domain.ShoppingListItem{}
+		if err := rows.Scan(&item.ID, &listID, &item.Name, &item.Quantity, &item.Unit, &item.Checked); err != nil {
+			return fmt.Errorf("failed to scan shopping list item: %w", err)
+		}
+
+		list := byID[listID]
+		list.Items = append(list.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read shopping list items: %w", err)
+	}
+
+	return nil
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const trainingPlanColumns = `id, user_id, plan_json, prompt_version, is_active, created_at`
+
 type TrainingRepository struct {
 	pool *pgxpool.Pool
 }
@@ -20,63 +22,228 @@ func NewTrainingRepository(pool *pgxpool.Pool) *TrainingRepository {
 	return &TrainingRepository{pool: pool}
 }
 
+// Create stores the plan as the user's active plan.
 func (r *TrainingRepository) Create(ctx context.Context, plan *domain.TrainingPlan) error {
 	plan.CreatedAt = time.Now().Unix()
+	plan.IsActive = true
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockUserPlans(ctx, tx, plan.UserID); err != nil {
+		return err
+	}
+
+	if err := deactivatePlans(ctx, tx, plan.UserID); err != nil {
+		return err
+	}
 
 	query := `
-		INSERT INTO training_plans (user_id, plan_json, created_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO training_plans (user_id, plan_json, prompt_version, is_active, created_at)
+		VALUES ($1, $2, $3, TRUE, $4)
 		RETURNING id
 	`
 
-	err := r.pool.QueryRow(ctx, query, plan.UserID, plan.PlanJSON, plan.CreatedAt).
+	err = tx.QueryRow(ctx, query, plan.UserID, plan.PlanJSON, plan.PromptVersion, plan.CreatedAt).
 		Scan(&plan.ID)
 
 	if err != nil {
 		return fmt.Errorf("failed to create training plan: %w", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit training plan: %w", err)
+	}
+
 	return nil
 }
 
 func (r *TrainingRepository) GetLatestByUserID(ctx context.Context, userID int64) (*domain.TrainingPlan, error) {
 	query := `
-		SELECT id, user_id, plan_json, created_at
+		SELECT ` + trainingPlanColumns + `
 		FROM training_plans WHERE user_id = $1
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
 
-	plan := &domain.TrainingPlan{}
-	err := r.pool.QueryRow(ctx, query, userID).Scan(
-		&plan.ID, &plan.UserID, &plan.PlanJSON, &plan.CreatedAt)
+	return r.getOne(ctx, query, userID)
+}
 
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("no training plan found")
-		}
-		return nil, fmt.Errorf("failed to get training plan: %w", err)
-	}
+func (r *TrainingRepository) GetActiveByUserID(ctx context.Context, userID int64) (*domain.TrainingPlan, error) {
+	query := `
+		SELECT ` + trainingPlanColumns + `
+		FROM training_plans WHERE user_id = $1 AND is_active
+	`
 
-	return plan, nil
+	return r.getOne(ctx, query, userID)
 }
 
 func (r *TrainingRepository) GetByID(ctx context.Context, id, userID int64) (*domain.TrainingPlan, error) {
 	query := `
-		SELECT id, user_id, plan_json, created_at
+		SELECT ` + trainingPlanColumns + `
 		FROM training_plans WHERE id = $1 AND user_id = $2
 	`
 
-	plan := &domain.TrainingPlan{}
-	err := r.pool.QueryRow(ctx, query, id, userID).Scan(
-		&plan.ID, &plan.UserID, &plan.PlanJSON, &plan.CreatedAt)
+	return r.getOne(ctx, query, id, userID)
+}
 
+func (r *TrainingRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.TrainingPlan, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	query := `
+		SELECT ` + trainingPlanColumns + `
+		FROM training_plans WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query training plans: %w", err)
+	}
+	defer rows.Close()
+
+	var plans []*domain.TrainingPlan
+	for rows.Next() {
+		plan, err := scanTrainingPlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan training plan: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read training plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+// SetActive makes the plan the user's only active plan.
+func (r *TrainingRepository) SetActive(ctx context.Context, id, userID int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockUserPlans(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx,
+		`SELECT TRUE FROM training_plans WHERE id = $1 AND user_id = $2 FOR UPDATE`,
+		id, userID).Scan(&exists)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("training plan not found")
+			return fmt.Errorf("training plan %w", domain.ErrNotFound)
+		}
+		return fmt.Errorf("failed to get training plan: %w", err)
+	}
+
+	if err := deactivatePlans(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE training_plans SET is_active = TRUE WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to activate training plan: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit training plan: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a plan. When it was the active plan, the most recent
+// remaining plan becomes active.
+func (r *TrainingRepository) Delete(ctx context.Context, id, userID int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockUserPlans(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	var wasActive bool
+	err = tx.QueryRow(ctx,
+		`DELETE FROM training_plans WHERE id = $1 AND user_id = $2 RETURNING is_active`,
+		id, userID).Scan(&wasActive)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("training plan %w", domain.ErrNotFound)
+		}
+		return fmt.Errorf("failed to delete training plan: %w", err)
+	}
+
+	if wasActive {
+		_, err := tx.Exec(ctx, `
+			UPDATE training_plans SET is_active = TRUE
+			WHERE id = (
+				SELECT id FROM training_plans WHERE user_id = $1
+				ORDER BY created_at DESC, id DESC
+				LIMIT 1
+			)
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("failed to activate training plan: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit training plan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TrainingRepository) getOne(ctx context.Context, query string, args ...any) (*domain.TrainingPlan, error) {
+	plan, err := scanTrainingPlan(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("training plan %w", domain.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get training plan: %w", err)
 	}
 
 	return plan, nil
 }
+
+// lockUserPlans locks the user's row for the rest of the transaction, so
+// concurrent generations, activations and deletions change the active plan
+// one at a time instead of both inserting an active plan.
+func lockUserPlans(ctx context.Context, tx pgx.Tx, userID int64) error {
+	var id int64
+	err := tx.QueryRow(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("user %w", domain.ErrNotFound)
+		}
+		return fmt.Errorf("failed to lock training plans: %w", err)
+	}
+	return nil
+}
+
+func deactivatePlans(ctx context.Context, tx pgx.Tx, userID int64) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE training_plans SET is_active = FALSE WHERE user_id = $1 AND is_active`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate training plans: %w", err)
+	}
+	return nil
+}
+
+func scanTrainingPlan(row pgx.Row) (*domain.TrainingPlan, error) {
+	plan := &domain.TrainingPlan{}
+	err := row.Scan(&plan.ID, &plan.UserID, &plan.PlanJSON, &plan.PromptVersion, &plan.IsActive, &plan.CreatedAt)
+	return plan, err
+}
@@ -42,13 +42,17 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, height, weight, goal, created_at
+		SELECT id, email, password_hash, height, weight, goal,
+			sex, birth_year, activity_level,
+			dietary_restrictions, allergens, disliked_foods, created_at
 		FROM users WHERE email = $1
 	`
 
 	user := &domain.User{}
 	err := r.pool.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Height, &user.Weight, &user.Goal, &user.CreatedAt)
+		&user.ID, &user.Email, &user.PasswordHash, &user.Height, &user.Weight, &user.Goal,
+		&user.Sex, &user.BirthYear, &user.ActivityLevel,
+		&user.Diet.Restrictions, &user.Diet.Allergens, &user.Diet.Dislikes, &user.CreatedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -62,17 +66,21 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, height, weight, goal, created_at
+		SELECT id, email, password_hash, height, weight, goal,
+			sex, birth_year, activity_level,
+			dietary_restrictions, allergens, disliked_foods, created_at
 		FROM users WHERE id = $1
 	`
 
 	user := &domain.User{}
 	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.Height, &user.Weight, &user.Goal, &user.CreatedAt)
+		&user.ID, &user.Email, &user.PasswordHash, &user.Height, &user.Weight, &user.Goal,
+		&user.Sex, &user.BirthYear, &user.ActivityLevel,
+		&user.Diet.Restrictions, &user.Diet.Allergens, &user.Diet.Dislikes, &user.CreatedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("user %w", domain.ErrNotFound)
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -83,19 +91,25 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, e
 func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
-		SET height = $1, weight = $2, goal = $3, updated_at = $4
-		WHERE id = $5
+		SET height = $1, weight = $2, goal = $3,
+			sex = $4, birth_year = $5, activity_level = $6,
+			dietary_restrictions = COALESCE($7::text[], '{}'), allergens = COALESCE($8::text[], '{}'),
+			disliked_foods = COALESCE($9::text[], '{}'), updated_at = $10
+		WHERE id = $11
 	`
 
 	result, err := r.pool.Exec(ctx, query,
-		user.Height, user.Weight, user.Goal, time.Now().Unix(), user.ID)
+		user.Height, user.Weight, user.Goal,
+		user.Sex, user.BirthYear, user.ActivityLevel,
+		user.Diet.Restrictions, user.Diet.Allergens, user.Diet.Dislikes,
+		time.Now().Unix(), user.ID)
 
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("user not found")
+		return fmt.Errorf("user %w", domain.ErrNotFound)
 	}
 
 	return nil
@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gymapp/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WorkoutRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWorkoutRepository(pool *pgxpool.Pool) *WorkoutRepository {
+	return &WorkoutRepository{pool: pool}
+}
+
+func (r *WorkoutRepository) Create(ctx context.Context, session *domain.WorkoutSession) error {
+	session.CreatedAt = time.Now().Unix()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO workout_sessions (user_id, name, notes, started_at, ended_at, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5::bigint, 0), $6)
+		RETURNING id
+	`
+
+	err = tx.QueryRow(ctx, query,
+		session.UserID, session.Name, session.Notes, session.StartedAt, session.EndedAt, session.CreatedAt).
+		Scan(&session.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create workout: %w", err)
+	}
+
+	if err := createWorkoutExercises(ctx, tx, session); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit workout: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WorkoutRepository) GetByID(ctx context.Context, id, userID int64) (*domain.WorkoutSession, error) {
+	query := `
+		SELECT id, user_id, name, notes, started_at, COALESCE(ended_at, 0), created_at
+		FROM workout_sessions WHERE id = $1 AND user_id = $2
+	`
+
+	session := &domain.WorkoutSession{}
+	err := r.pool.QueryRow(ctx, query, id, userID).Scan(
+		&session.ID, &session.UserID, &session.Name, &session.Notes,
+		&session.StartedAt, &session.EndedAt, &session.CreatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("workout %w", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get workout: %w", err)
+	}
+
+	if err := r.loadExercises(ctx, []*domain.WorkoutSession{session}); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *WorkoutRepository) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.WorkoutSession, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	query := `
+		SELECT id, user_id, name, notes, started_at, COALESCE(ended_at, 0), created_at
+		FROM workout_sessions WHERE user_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workouts: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*domain.WorkoutSession
+	for rows.Next() {
+		session := &domain.WorkoutSession{}
+		if err := rows.Scan(&session.ID, &session.UserID, &session.Name, &session.Notes,
+			&session.StartedAt, &session.EndedAt, &session.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workout: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read workouts: %w", err)
+	}
+
+	if err := r.loadExercises(ctx, sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Update replaces the session fields and its full exercise list.
+func (r *WorkoutRepository) Update(ctx context.Context, session *domain.WorkoutSession) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE workout_sessions
+		SET name = $1, notes = $2, started_at = $3, ended_at = NULLIF($4::bigint, 0)
+		WHERE id = $5 AND user_id = $6
+		RETURNING created_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		session.Name, session.Notes, session.StartedAt, session.EndedAt, session.ID, session.UserID).
+		Scan(&session.CreatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("workout %w", domain.ErrNotFound)
+		}
+		return fmt.Errorf("failed to update workout: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM workout_exercises WHERE session_id = $1`, session.ID); err != nil {
+		return fmt.Errorf("failed to clear workout exercises: %w", err)
+	}
+
+	if err := createWorkoutExercises(ctx, tx, session); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit workout: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WorkoutRepository) Delete(ctx context.Context, id, userID int64) error {
+	query := `DELETE FROM workout_sessions WHERE id = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete workout: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("workout %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+func createWorkoutExercises(ctx context.Context, tx pgx.Tx, session *domain.WorkoutSession) error {
+	for i := range session.Exercises {
+		ex := &session.Exercises[i]
+
+		err := tx.QueryRow(ctx, `
+			INSERT INTO workout_exercises (session_id, position, exercise_id, name, notes)
+			VALUES ($1, $2, NULLIF($3::bigint, 0), $4, $5)
+			RETURNING id
+		`, session.ID, i, ex.ExerciseID, ex.Name, ex.Notes).Scan(&ex.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create workout exercise: %w", err)
+		}
+
+		for j := range ex.Sets {
+			set := &ex.Sets[j]
+
+			err := tx.QueryRow(ctx, `
+				INSERT INTO workout_sets (exercise_id, position, reps, weight_kg, rpe, rest_seconds)
+				VALUES ($1, $2, $3, $4, NULLIF($5::numeric, 0), NULLIF($6::int, 0))
+				RETURNING id
+			`, ex.ID, j, set.Reps, set.WeightKg, set.RPE, set.RestSeconds).Scan(&set.ID)
+			if err != nil {
+				return fmt.Errorf("failed to create workout set: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadExercises attaches exercises and sets to sessions using one query per
+// table rather than one per session.
+func (r *WorkoutRepository) loadExercises(ctx context.Context, sessions []*domain.WorkoutSession) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.WorkoutSession, len(sessions))
+	sessionIDs := make([]int64, 0, len(sessions))
+	for _, session := range sessions {
+		session.Exercises = []domain.WorkoutExercise{}
+		byID[session.ID] = session
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, session_id, COALESCE(exercise_id, 0), name, notes
+		FROM workout_exercises WHERE session_id = ANY($1)
+		ORDER BY session_id, position
+	`, sessionIDs)
+	if err != nil {
+		return fmt.Errorf("failed to query workout exercises: %w", err)
+	}
+	defer rows.Close()
+
+	type exerciseRef struct {
+		session *domain.WorkoutSession
+		index   int
+	}
+	exercises := make(map[int64]exerciseRef)
+	var exerciseIDs []int64
+
+	for rows.Next() {
+		var sessionID int64
+		ex := domain.WorkoutExercise{Sets: []domain.WorkoutSet{}}
+		if err := rows.Scan(&ex.ID, &sessionID, &ex.ExerciseID, &ex.Name, &ex.Notes); err != nil {
+			return fmt.Errorf("failed to scan workout exercise: %w", err)
+		}
+
+		session := byID[sessionID]
+		session.Exercises = append(session.Exercises, ex)
+		exercises[ex.ID] = exerciseRef{session: session, index: len(session.Exercises) - 1}
+		exerciseIDs = append(exerciseIDs, ex.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read workout exercises: %w", err)
+	}
+	rows.Close()
+
+	if len(exerciseIDs) == 0 {
+		return nil
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT id, exercise_id, reps, weight_kg, COALESCE(rpe, 0), COALESCE(rest_seconds, 0)
+		FROM workout_sets WHERE exercise_id = ANY($1)
+		ORDER BY exercise_id, position
+	`, exerciseIDs)
+	if err != nil {
+		return fmt.Errorf("failed to query workout sets: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exerciseID int64
+		set := domain.WorkoutSet{}
+		if err := rows.Scan(&set.ID, &exerciseID, &set.Reps, &set.WeightKg, &set.RPE, &set.RestSeconds); err != nil {
+			return fmt.Errorf("failed to scan workout set: %w", err)
+		}
+
+		ref := exercises[exerciseID]
+		ex := &ref.session.Exercises[ref.index]
+		ex.Sets = append(ex.Sets, set)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read workout sets: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"gymapp/pkg/utils"
+)
+
+// Job is a unit of periodic background work.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Locker grants exclusive access to a key across every replica sharing the
+// same database. TryLock returns ok=false without blocking when another
+// holder already has the key.
+type Locker interface {
+	TryLock(ctx context.Context, key int64) (unlock func(), ok bool, err error)
+}
+
+type Scheduler struct {
+	locker Locker
+	logger *utils.Logger
+	jobs   []Job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(locker Locker, logger *utils.Logger) *Scheduler {
+	return &Scheduler{
+		locker: locker,
+		logger: logger,
+	}
+}
+
+// Register adds a job. It must be called before Start.
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every registered job once immediately and then on its interval
+// until Stop is called or ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop cancels all jobs and waits for in-flight runs to return.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	runCtx, cancel := context.WithTimeout(ctx, job.Interval)
+	defer cancel()
+
+	unlock, ok, err := s.locker.TryLock(runCtx, lockKey(job.Name))
+	if err != nil {
+		s.logger.Errorf("job %s: failed to acquire lock: %v", job.Name, err)
+		return
+	}
+	if !ok {
+		s.logger.Debugf("job %s: skipped, running on another instance", job.Name)
+		return
+	}
+	defer unlock()
+
+	start := time.Now()
+	if err := job.Run(runCtx); err != nil {
+		s.logger.Errorf("job %s: failed after %s: %v", job.Name, time.Since(start), err)
+		return
+	}
+
+	s.logger.Infof("job %s: completed in %s", job.Name, time.Since(start))
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("gymapp.scheduler." + name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gymapp/pkg/utils"
+)
+
+type fakeLocker struct {
+	grant    bool
+	unlocked atomic.Int32
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	if !l.grant {
+		return nil, false, nil
+	}
+	return func() { l.unlocked.Add(1) }, true, nil
+}
+
+func TestSchedulerRunsJobAndStops(t *testing.T) {
+	locker := &fakeLocker{grant: true}
+	s := New(locker, utils.NewLogger())
+
+	var runs atomic.Int32
+	s.Register(Job{
+		Name:     "test",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	s.Start(context.Background())
+	time.Sleep(35 * time.Millisecond)
+	s.Stop()
+
+	got := runs.Load()
+	if got < 2 {
+		t.Errorf("expected at least 2 runs, got %d", got)
+	}
+
+	if unlocked := locker.unlocked.Load(); unlocked != got {
+		t.Errorf("expected %d unlocks, got %d", got, unlocked)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if runs.Load() != got {
+		t.Error("job ran after Stop")
+	}
+}
+
+func TestSchedulerSkipsWhenLocked(t *testing.T) {
+	s := New(&fakeLocker{grant: false}, utils.NewLogger())
+
+	var runs atomic.Int32
+	s.Register(Job{
+		Name:     "test",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	s.Start(context.Background())
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+
+	if runs.Load() != 0 {
+		t.Errorf("expected job to be skipped, ran %d times", runs.Load())
+	}
+}
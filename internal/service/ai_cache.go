@@ -0,0 +1,243 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+)
+
+// aiCacheMetrics counts cache lookups. It is published through expvar and
+// served at /debug/vars.
+var aiCacheMetrics = expvar.NewMap("ai_cache")
+
+// cachingBackend answers repeated prompts from a cache instead of calling
+// the provider again. Entries live in an in-memory LRU and, when a store is
+// configured, in Postgres so that replicas and restarts share them. Requests
+// with images or SkipCache set always go to the provider.
+type cachingBackend struct {
+	next        chatBackend
+	provider    string
+	model       string
+	visionModel string
+	ttl         time.Duration
+	memory      *lruCache
+	store       domain.AICacheRepository
+	now         func() time.Time
+}
+
+func newCachingBackend(next chatBackend, provider string, cfg *config.AIConfig, store domain.AICacheRepository) *cachingBackend {
+	return &cachingBackend{
+		next:        next,
+		provider:    provider,
+		model:       cfg.Model,
+		visionModel: cfg.VisionModel,
+		ttl:         cfg.CacheTTL,
+		memory:      newLRUCache(cfg.CacheSize),
+		store:       store,
+		now:         time.Now,
+	}
+}
+
+func (b *cachingBackend) complete(ctx context.Context, req *chatRequest) (string, error) {
+	if hasImages(req) {
+		return b.next.complete(ctx, req)
+	}
+
+	if req.SkipCache {
+		aiCacheMetrics.Add("bypassed", 1)
+		return b.next.complete(ctx, req)
+	}
+
+	model := b.model
+	if req.Vision {
+		model = b.visionModel
+	}
+	key := aiCacheKey(b.provider, model, req)
+
+	if reply, ok := b.lookup(ctx, key); ok {
+		if req.Stream != nil {
+			if err := req.Stream(reply); err != nil {
+				return "", err
+			}
+		}
+		return reply, nil
+	}
+
+	aiCacheMetrics.Add("misses", 1)
+	reply, err := b.next.complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if b.cacheable(req, reply) {
+		b.save(ctx, key, model, reply)
+	}
+
+	return reply, nil
+}
+
+// cacheable reports whether a reply may be stored. A reply the caller
+// cannot use would make every identical request fail the same way, so it is
+// only kept once it is valid JSON and passes the request's Validate.
+func (b *cachingBackend) cacheable(req *chatRequest, reply string) bool {
+	if req.JSONMode && !json.Valid([]byte(extractJSONObject(reply))) {
+		aiCacheMetrics.Add("rejected", 1)
+		return false
+	}
+	if req.Validate != nil && req.Validate(reply) != nil {
+		aiCacheMetrics.Add("rejected", 1)
+		return false
+	}
+	return true
+}
+
+func (b *cachingBackend) lookup(ctx context.Context, key string) (string, bool) {
+	now := b.now()
+	if reply, ok := b.memory.get(key, now); ok {
+		aiCacheMetrics.Add("memory_hits", 1)
+		return reply, true
+	}
+
+	if b.store == nil {
+		return "", false
+	}
+
+	entry, err := b.store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			aiCacheMetrics.Add("store_errors", 1)
+		}
+		return "", false
+	}
+
+	aiCacheMetrics.Add("store_hits", 1)
+	b.memory.set(key, entry.Reply, time.Unix(entry.ExpiresAt, 0))
+	return entry.Reply, true
+}
+
+func (b *cachingBackend) save(ctx context.Context, key, model, reply string) {
+	now := b.now()
+	expiresAt := now.Add(b.ttl)
+	b.memory.set(key, reply, expiresAt)
+
+	if b.store == nil {
+		return
+	}
+
+	err := b.store.Set(ctx, &domain.AICacheEntry{
+		Key:       key,
+		Provider:  b.provider,
+		Model:     model,
+		Reply:     reply,
+		CreatedAt: now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		aiCacheMetrics.Add("store_errors", 1)
+	}
+}
+
+func hasImages(req *chatRequest) bool {
+	for _, msg := range req.Messages {
+		if len(msg.Images) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// aiCacheKey hashes everything that determines a reply: provider, model,
+// output mode and the normalized conversation.
+func aiCacheKey(provider, model string, req *chatRequest) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%t", provider, model, req.JSONMode)
+	for _, msg := range req.Messages {
+		fmt.Fprintf(h, "\x00%s\x00%s", msg.Role, normalizePrompt(msg.Content))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizePrompt lowercases a prompt and collapses whitespace, so prompts
+// that differ only in case or spacing share a cache entry.
+func normalizePrompt(prompt string) string {
+	return strings.ToLower(strings.Join(strings.Fields(prompt), " "))
+}
+
+// lruCache is a fixed-size map that evicts the least recently used entry
+// and drops entries once they expire.
+type lruCache struct {
+	capacity int
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	reply     string
+	expiresAt time.Time
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) get(key string, now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if !now.Before(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return "", false
+	}
+
+	c.order.MoveToFront(el)
+	return entry.reply, true
+}
+
+func (c *lruCache) set(key, reply string, expiresAt time.Time) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.reply = reply
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, reply: reply, expiresAt: expiresAt})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+)
+
+// countingBackend replies with a fixed string and counts calls.
+type countingBackend struct {
+	reply string
+	calls int
+}
+
+func (b *countingBackend) complete(ctx context.Context, req *chatRequest) (string, error) {
+	b.calls++
+	return b.reply, nil
+}
+
+type fakeAICacheRepo struct {
+	entries map[string]*domain.AICacheEntry
+}
+
+func (r *fakeAICacheRepo) Get(ctx context.Context, key string) (*domain.AICacheEntry, error) {
+	if entry, ok := r.entries[key]; ok {
+		return entry, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeAICacheRepo) Set(ctx context.Context, entry *domain.AICacheEntry) error {
+	r.entries[entry.Key] = entry
+	return nil
+}
+
+func (r *fakeAICacheRepo) DeleteExpired(ctx context.Context) error {
+	return nil
+}
+
+func chatPrompt(prompt string) *chatRequest {
+	return &chatRequest{Messages: []ChatMessage{{Role: "user", Content: prompt}}, JSONMode: true}
+}
+
+func TestCachingBackend(t *testing.T) {
+	cfg := &config.AIConfig{Model: "gpt", CacheTTL: time.Hour, CacheSize: 10}
+	next := &countingBackend{reply: `{"recipes": []}`}
+	store := &fakeAICacheRepo{entries: make(map[string]*domain.AICacheEntry)}
+	backend := newCachingBackend(next, "openai", cfg, store)
+	ctx := context.Background()
+
+	backend.complete(ctx, chatPrompt("Ingredients: Eggs,  spinach\n"))
+	reply, _ := backend.complete(ctx, chatPrompt("ingredients: eggs, spinach"))
+	if next.calls != 1 || reply != next.reply {
+		t.Fatalf("normalized prompt should hit the cache, got %d calls", next.calls)
+	}
+
+	bypass := chatPrompt("ingredients: eggs, spinach")
+	bypass.SkipCache = true
+	backend.complete(ctx, bypass)
+	if next.calls != 2 {
+		t.Errorf("SkipCache should call the provider, got %d calls", next.calls)
+	}
+
+	var streamed string
+	cached := chatPrompt("ingredients: eggs, spinach")
+	cached.Stream = func(delta string) error {
+		streamed += delta
+		return nil
+	}
+	backend.complete(ctx, cached)
+	if next.calls != 2 || streamed != next.reply {
+		t.Errorf("cached reply should be streamed without a call, got %q after %d calls", streamed, next.calls)
+	}
+
+	// A fresh process shares entries through the store.
+	other := newCachingBackend(next, "openai", cfg, store)
+	other.complete(ctx, chatPrompt("ingredients: eggs, spinach"))
+	if next.calls != 2 {
+		t.Errorf("store should serve the entry, got %d calls", next.calls)
+	}
+
+	// Other models and unparseable replies are not shared or stored.
+	cfg.Model = "gpt-large"
+	next.reply = "not json"
+	third := newCachingBackend(next, "openai", cfg, store)
+	third.complete(ctx, chatPrompt("ingredients: eggs, spinach"))
+	third.complete(ctx, chatPrompt("ingredients: eggs, spinach"))
+	if next.calls != 4 {
+		t.Errorf("expected a miss per call for a new model with invalid replies, got %d calls", next.calls)
+	}
+
+	// Valid JSON the caller cannot parse is not stored either.
+	next.reply = `{"recipes": "none"}`
+	rejected := func() *chatRequest {
+		req := chatPrompt("ingredients: tofu")
+		req.Validate = func(reply string) error {
+			_, err := parseDishes(reply)
+			return err
+		}
+		return req
+	}
+	third.complete(ctx, rejected())
+	third.complete(ctx, rejected())
+	if next.calls != 6 {
+		t.Errorf("expected a miss per call for replies the caller rejects, got %d calls", next.calls)
+	}
+}
+
+func TestLRUCacheEvictsAndExpires(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newLRUCache(2)
+
+	cache.set("a", "1", now.Add(time.Minute))
+	cache.set("b", "2", now.Add(time.Minute))
+	cache.get("a", now)
+	cache.set("c", "3", now.Add(time.Second))
+
+	if _, ok := cache.get("b", now); ok {
+		t.Error("least recently used entry should be evicted")
+	}
+	if reply, ok := cache.get("a", now); !ok || reply != "1" {
+		t.Error("recently used entry should be kept")
+	}
+	if _, ok := cache.get("c", now.Add(time.Second)); ok {
+		t.Error("expired entry should not be returned")
+	}
+}
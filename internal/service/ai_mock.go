@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"gymapp/internal/domain"
+)
+
+// mockStreamChunk is how many bytes of a reply the mock streams at a time.
+const mockStreamChunk = 48
+
+// mockPantry is the pool DetectIngredients draws from.
+var mockPantry = []string{
+	"chicken breast", "brown rice", "broccoli", "eggs", "spinach",
+	"sweet potato", "greek yogurt", "oats", "salmon", "avocado",
+	"tomato", "chickpeas",
+}
+
+// mockMenu holds a few dishes per meal for GenerateMealPlan to rotate
+// through; quantities are scaled to the requested calories.
+var mockMenu = map[string][]domain.Dish{
+	domain.MealBreakfast: {
+		mockDish("Overnight Oats with Berries", "oats", "greek yogurt", "blueberries"),
+		mockDish("Spinach Omelette", "eggs", "spinach", "whole grain toast"),
+		mockDish("Peanut Butter Banana Toast", "whole grain toast", "peanut butter", "banana"),
+	},
+	domain.MealLunch: {
+		mockDish("Chicken Rice Bowl", "chicken breast", "brown rice", "broccoli"),
+		mockDish("Chickpea Salad", "chickpeas", "tomato", "cucumber"),
+		mockDish("Turkey Wrap", "turkey breast", "tortilla", "lettuce"),
+	},
+	domain.MealDinner: {
+		mockDish("Baked Salmon with Sweet Potato", "salmon", "sweet potato", "green beans"),
+		mockDish("Beef Stir-Fry", "lean beef", "bell pepper", "brown rice"),
+		mockDish("Lentil Curry", "lentils", "coconut milk", "spinach"),
+	},
+	domain.MealSnack: {
+		mockDish("Greek Yogurt with Honey", "greek yogurt", "honey", "walnuts"),
+		mockDish("Apple with Almond Butter", "apple", "almond butter", "cinnamon"),
+	},
+}
+
+func mockDish(name string, ingredients ...string) domain.Dish {
+	dish := domain.Dish{Name: name, Servings: 1, PrepTimeMinutes: 15}
+	for _, ing := range ingredients {
+		dish.Ingredients = append(dish.Ingredients, domain.DishIngredient{Name: ing, Quantity: 100, Unit: "g"})
+	}
+	dish.Steps = []string{"Prepare the ingredients.", "Combine and serve."}
+	return dish
+}
+
+// MockAIProvider returns canned content without calling any API. It is used
+// when no AI backend is configured.
+type MockAIProvider struct{}
+
+func NewMockAIProvider() *MockAIProvider {
+	return &MockAIProvider{}
+}
+
+func (p *MockAIProvider) GenerateRecipes(ctx context.Context, input *domain.RecipeInput) (string, error) {
+	reply := recipeReply{
+		Recipes: []domain.Dish{
+			{
+				Name:            "Healthy Salad Bowl with " + input.Ingredients,
+				Servings:        1,
+				PrepTimeMinutes: 15,
+				Calories:        350,
+				ProteinGrams:    20,
+				CarbsGrams:      35,
+				FatGrams:        14,
+				Ingredients: []domain.DishIngredient{
+					{Name: "mixed greens", Quantity: 100, Unit: "g"},
+					{Name: input.Ingredients, Quantity: 150, Unit: "g"},
+					{Name: "olive oil", Quantity: 1, Unit: "tbsp"},
+				},
+				Steps: []string{
+					"Wash and dry the greens.",
+					"Chop the remaining ingredients and add them to a bowl.",
+					"Dress with olive oil and toss.",
+				},
+			},
+		},
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mock recipes: %w", err)
+	}
+
+	return mockReply(string(data), input.Stream)
+}
+
+func (p *MockAIProvider) GenerateTrainingPlan(ctx context.Context, input *domain.TrainingPlanInput) (string, error) {
+	availableDays := input.AvailableDays
+
+	sessions := []domain.PlanDay{
+		{
+			Focus: "Full body strength",
+			Exercises: []domain.PlanExercise{
+				{Name: "Barbell Squat", Sets: 4, Reps: 8, Intensity: domain.IntensityHigh},
+				{Name: "Bench Press", Sets: 4, Reps: 8, Intensity: domain.IntensityHigh},
+				{Name: "Bent-Over Row", Sets: 3, Reps: 10, Intensity: domain.IntensityModerate},
+			},
+		},
+		{
+			Focus: "Conditioning",
+			Exercises: []domain.PlanExercise{
+				{Name: "Stationary Bike", DurationMinutes: 30, Intensity: domain.IntensityModerate},
+				{Name: "Plank", Sets: 3, Reps: 1, Intensity: domain.IntensityLow},
+			},
+		},
+		{
+			Focus: "Posterior chain",
+			Exercises: []domain.PlanExercise{
+				{Name: "Romanian Deadlift", Sets: 3, Reps: 10, Intensity: domain.IntensityModerate},
+				{Name: "Walking Lunge", Sets: 3, Reps: 12, Intensity: domain.IntensityModerate},
+				{Name: "Brisk Walk", DurationMinutes: 20, Intensity: domain.IntensityLow},
+			},
+		},
+	}
+
+	if lib := newExerciseCatalog(input.Exercises); lib != nil {
+		for _, session := range sessions {
+			for i := range session.Exercises {
+				if entry := lib.resolve(session.Exercises[i]); entry != nil {
+					session.Exercises[i].ExerciseID = entry.ID
+				}
+			}
+		}
+	}
+
+	var days []domain.PlanDay
+	for i := 0; i < availableDays; i++ {
+		day := sessions[i%len(sessions)]
+		day.Day = weekdays[i*7/availableDays]
+		days = append(days, day)
+	}
+
+	nutrition := fmt.Sprintf("Caloric deficit of 500-750 kcal/day to lose %.1fkg", input.WeightKg-float64(input.TargetWeight))
+	if t := input.Targets; t != nil {
+		nutrition = fmt.Sprintf("Eat about %d kcal/day with %dg protein, %dg carbs and %dg fat",
+			t.Calories, t.ProteinGrams, t.CarbsGrams, t.FatGrams)
+	}
+
+	plan := domain.WorkoutPlan{
+		DurationWeeks: 12,
+		Nutrition:     nutrition,
+		Recovery:      "Sleep 7-9 hours and keep at least one rest day between strength sessions",
+	}
+	for week := 1; week <= 4; week++ {
+		plan.Weeks = append(plan.Weeks, domain.PlanWeek{Week: week, Days: days})
+	}
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mock plan: %w", err)
+	}
+
+	return mockReply(string(data), input.Stream)
+}
+
+// GenerateMealPlan fills each slot from mockMenu, skipping dishes listed in
+// Avoid or containing the user's allergens when it can, and splits the slot
+// calories 30/40/30 between protein, carbs and fat.
+func (p *MockAIProvider) GenerateMealPlan(ctx context.Context, input *domain.MealPlanInput) (string, error) {
+	avoid := make(map[string]bool, len(input.Avoid))
+	for _, name := range input.Avoid {
+		avoid[name] = true
+	}
+	rules := newDietRules(input.Diet)
+
+	var reply mealPlanReply
+	for _, slot := range input.Slots {
+		menu := mockMenu[slot.Meal]
+		if len(menu) == 0 {
+			continue
+		}
+
+		i := max(slices.Index(weekdays, slot.Day), 0)
+		dish := menu[i%len(menu)]
+		for j := range menu {
+			if candidate := menu[(i+j)%len(menu)]; !avoid[candidate.Name] && len(rules.screen(&candidate)) == 0 {
+				dish = candidate
+				break
+			}
+		}
+
+		dish.Calories = slot.Calories
+		dish.ProteinGrams = roundGrams(float64(slot.Calories) * 0.3 / 4)
+		dish.CarbsGrams = roundGrams(float64(slot.Calories) * 0.4 / 4)
+		dish.FatGrams = roundGrams(float64(slot.Calories) * 0.3 / 9)
+		dish.Ingredients = append([]domain.DishIngredient(nil), dish.Ingredients...)
+		for k := range dish.Ingredients {
+			dish.Ingredients[k].Quantity = roundGrams(float64(slot.Calories) / 6)
+		}
+
+		reply.Meals = append(reply.Meals, mealReply{Day: slot.Day, Meal: slot.Meal, Dish: dish})
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mock meal plan: %w", err)
+	}
+
+	return mockReply(string(data), input.Stream)
+}
+
+// mockReply returns a canned reply, first passing it to stream in small
+// pieces when streaming was requested.
+func mockReply(reply string, stream domain.StreamFunc) (string, error) {
+	if stream == nil {
+		return reply, nil
+	}
+
+	for rest := reply; rest != ""; {
+		n := min(mockStreamChunk, len(rest))
+		if err := stream(rest[:n]); err != nil {
+			return "", err
+		}
+		rest = rest[n:]
+	}
+
+	return reply, nil
+}
+
+// DetectIngredients picks three pantry items from a hash of the image so the
+// same upload always yields the same ingredients.
+func (p *MockAIProvider) DetectIngredients(ctx context.Context, imageData []byte, mimeType string, usage *domain.TokenUsage) ([]string, error) {
+	sum := sha256.Sum256(imageData)
+
+	var ingredients []string
+	seen := make(map[int]bool)
+	for _, b := range sum {
+		i := int(b) % len(mockPantry)
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		ingredients = append(ingredients, mockPantry[i])
+		if len(ingredients) == 3 {
+			break
+		}
+	}
+
+	return ingredients, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+)
+
+// ollamaBackend talks to a local Ollama server's /api/chat endpoint.
+type ollamaBackend struct {
+	baseURL      string
+	model        string
+	visionModel  string
+	client       *http.Client
+	streamClient *http.Client
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   string          `json:"format,omitempty"`
+	Options  map[string]any  `json:"options,omitempty"`
+}
+
+// ollamaMessage attaches images as a list of base64 strings.
+type ollamaMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
+}
+
+// ollamaChatResponse is a reply or, when streaming, one chunk of it. The
+// final chunk carries the token counts.
+type ollamaChatResponse struct {
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	PromptEvalCount int64         `json:"prompt_eval_count"`
+	EvalCount       int64         `json:"eval_count"`
+}
+
+func (r *ollamaChatResponse) tokens() domain.TokenUsage {
+	return domain.TokenUsage{PromptTokens: r.PromptEvalCount, CompletionTokens: r.EvalCount}
+}
+
+func newOllamaBackend(cfg *config.AIConfig, client, streamClient *http.Client) *ollamaBackend {
+	return &ollamaBackend{
+		baseURL:      cfg.BaseURL,
+		model:        cfg.Model,
+		visionModel:  cfg.VisionModel,
+		client:       client,
+		streamClient: streamClient,
+	}
+}
+
+func (b *ollamaBackend) complete(ctx context.Context, chatReq *chatRequest) (string, error) {
+	model := b.model
+	if chatReq.Vision {
+		model = b.visionModel
+	}
+
+	messages := make([]ollamaMessage, 0, len(chatReq.Messages))
+	for _, msg := range chatReq.Messages {
+		m := ollamaMessage{Role: msg.Role, Content: msg.Content}
+		for _, img := range msg.Images {
+			m.Images = append(m.Images, base64.StdEncoding.EncodeToString(img.Data))
+		}
+		messages = append(messages, m)
+	}
+
+	req := ollamaChatRequest{
+		Model:    model,
+		Messages: messages,
+		Stream:   chatReq.Stream != nil,
+		Options:  map[string]any{"temperature": 0.7},
+	}
+	if chatReq.JSONMode {
+		req.Format = "json"
+	}
+
+	client := b.client
+	if req.Stream {
+		client = b.streamClient
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		b.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", requestError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp)
+	}
+
+	if req.Stream {
+		return readOllamaStream(resp.Body, chatReq.Stream, chatReq.Usage)
+	}
+
+	var chatResp ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if chatResp.Message.Content == "" {
+		return "", fmt.Errorf("empty message in response")
+	}
+
+	chatReq.Usage.Add(chatResp.tokens())
+
+	return chatResp.Message.Content, nil
+}
+
+// readOllamaStream assembles a streamed reply from Ollama's newline-delimited
+// JSON chunks, passing each piece to stream as it arrives and adding the
+// final token counts to usage.
+func readOllamaStream(body io.Reader, stream domain.StreamFunc, usage *domain.TokenUsage) (string, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+
+	var reply strings.Builder
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return "", fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+
+		if delta := chunk.Message.Content; delta != "" {
+			reply.WriteString(delta)
+			if err := stream(delta); err != nil {
+				return "", err
+			}
+		}
+
+		if chunk.Done {
+			usage.Add(chunk.tokens())
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	if reply.Len() == 0 {
+		return "", fmt.Errorf("empty message in response")
+	}
+
+	return reply.String(), nil
+}
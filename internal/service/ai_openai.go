@@ -0,0 +1,239 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+)
+
+// openAIBackend talks to any API implementing OpenAI's /chat/completions.
+type openAIBackend struct {
+	apiKey       string
+	baseURL      string
+	model        string
+	visionModel  string
+	client       *http.Client
+	streamClient *http.Client
+}
+
+type openAIChatRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Stream         bool                  `json:"stream,omitempty"`
+	StreamOptions  *openAIStreamOptions  `json:"stream_options,omitempty"`
+}
+
+// openAIStreamOptions asks for a final chunk carrying the token usage,
+// which streamed completions otherwise leave out.
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+}
+
+func (u *openAIUsage) tokens() domain.TokenUsage {
+	if u == nil {
+		return domain.TokenUsage{}
+	}
+	return domain.TokenUsage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+// openAIMessage carries either a plain string or, when images are attached,
+// an array of content parts.
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// openAIStreamChunk is one "data:" event of a streamed completion.
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+func newOpenAIBackend(cfg *config.AIConfig, client, streamClient *http.Client) *openAIBackend {
+	return &openAIBackend{
+		apiKey:       cfg.APIKey,
+		baseURL:      cfg.BaseURL,
+		model:        cfg.Model,
+		visionModel:  cfg.VisionModel,
+		client:       client,
+		streamClient: streamClient,
+	}
+}
+
+func (b *openAIBackend) complete(ctx context.Context, chatReq *chatRequest) (string, error) {
+	model := b.model
+	if chatReq.Vision {
+		model = b.visionModel
+	}
+
+	req := openAIChatRequest{
+		Model:       model,
+		Messages:    toOpenAIMessages(chatReq.Messages),
+		Temperature: 0.7,
+	}
+	if chatReq.JSONMode {
+		req.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+	}
+
+	client := b.client
+	if chatReq.Stream != nil {
+		req.Stream = true
+		req.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+		client = b.streamClient
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		b.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+b.apiKey)
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", requestError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp)
+	}
+
+	if chatReq.Stream != nil {
+		return readOpenAIStream(resp.Body, chatReq.Stream, chatReq.Usage)
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("no choices in response")
+	}
+
+	chatReq.Usage.Add(chatResp.Usage.tokens())
+
+	return chatResp.Choices[0].Message.Content, nil
+}
+
+// readOpenAIStream assembles a streamed completion from its server-sent
+// events, passing each content delta to stream as it arrives and adding the
+// usage chunk to usage.
+func readOpenAIStream(body io.Reader, stream domain.StreamFunc, usage *domain.TokenUsage) (string, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+
+	var reply strings.Builder
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+
+		usage.Add(chunk.Usage.tokens())
+
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		reply.WriteString(delta)
+		if err := stream(delta); err != nil {
+			return "", err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	if reply.Len() == 0 {
+		return "", fmt.Errorf("empty stream")
+	}
+
+	return reply.String(), nil
+}
+
+func toOpenAIMessages(messages []ChatMessage) []openAIMessage {
+	out := make([]openAIMessage, 0, len(messages))
+	for _, msg := range messages {
+		if len(msg.Images) == 0 {
+			out = append(out, openAIMessage{Role: msg.Role, Content: msg.Content})
+			continue
+		}
+
+		parts := []openAIContentPart{{Type: "text", Text: msg.Content}}
+		for _, img := range msg.Images {
+			parts = append(parts, openAIContentPart{
+				Type: "image_url",
+				ImageURL: &openAIImageURL{
+					URL: "data:" + img.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(img.Data),
+				},
+			})
+		}
+		out = append(out, openAIMessage{Role: msg.Role, Content: parts})
+	}
+
+	return out
+}
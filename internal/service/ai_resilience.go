@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+)
+
+// maxErrorBody bounds how much of a failed reply is kept for the logs.
+const maxErrorBody = 4 << 10
+
+var errCircuitOpen = fmt.Errorf("%w: circuit breaker open", domain.ErrAIUnavailable)
+
+// apiError is a non-200 reply from a provider. 429 and 5xx replies wrap
+// domain.ErrAIUnavailable and are retried. The body is kept for the logs;
+// handlers never show it to clients.
+type apiError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("api error: status %d, body: %s", e.StatusCode, e.Body)
+}
+
+func (e *apiError) Unwrap() error {
+	if e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500 {
+		return domain.ErrAIUnavailable
+	}
+	return nil
+}
+
+func newAPIError(resp *http.Response) *apiError {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return &apiError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Body:       string(data),
+	}
+}
+
+// requestError wraps a failed round trip. Network errors and timeouts mean
+// the provider is unavailable; a cancelled caller does not.
+func requestError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("api request failed: %w", err)
+	}
+	return fmt.Errorf("%w: api request failed: %v", domain.ErrAIUnavailable, err)
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as
+// an HTTP date, returning 0 when it is absent or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+
+	return 0
+}
+
+// resilientBackend retries calls that failed because the provider was
+// unavailable, with jittered exponential backoff that honors Retry-After,
+// and stops calling a provider that keeps failing until its cooldown ends.
+// A streamed call is only retried when nothing has been streamed yet.
+type resilientBackend struct {
+	next       chatBackend
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	breaker    *circuitBreaker
+}
+
+func newResilientBackend(next chatBackend, cfg *config.AIConfig) *resilientBackend {
+	return &resilientBackend{
+		next:       next,
+		maxRetries: cfg.MaxRetries,
+		baseDelay:  cfg.RetryBaseDelay,
+		maxDelay:   cfg.RetryMaxDelay,
+		breaker:    newCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+func (b *resilientBackend) complete(ctx context.Context, req *chatRequest) (string, error) {
+	streamed := false
+	if stream := req.Stream; stream != nil {
+		wrapped := *req
+		wrapped.Stream = func(delta string) error {
+			streamed = true
+			return stream(delta)
+		}
+		req = &wrapped
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := b.breaker.allow(); err != nil {
+			return "", err
+		}
+
+		reply, err := b.next.complete(ctx, req)
+		unavailable := errors.Is(err, domain.ErrAIUnavailable)
+		b.breaker.record(!unavailable)
+
+		if err == nil || !unavailable || streamed || attempt >= b.maxRetries {
+			return reply, err
+		}
+
+		delay, ok := b.backoff(attempt, err)
+		if !ok {
+			return "", err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before the next attempt: the exponential delay
+// for attempt with up to half of it randomized, raised to the provider's
+// Retry-After. It reports false when Retry-After exceeds the maximum delay,
+// since waiting that long would outlast the client.
+func (b *resilientBackend) backoff(attempt int, err error) (time.Duration, bool) {
+	delay := b.baseDelay << attempt
+	if delay <= 0 || delay > b.maxDelay {
+		delay = b.maxDelay
+	}
+	if half := int64(delay / 2); half > 0 {
+		delay = delay/2 + time.Duration(rand.Int64N(half+1))
+	}
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		if apiErr.RetryAfter > b.maxDelay {
+			return 0, false
+		}
+		delay = max(delay, apiErr.RetryAfter)
+	}
+
+	return delay, true
+}
+
+// circuitBreaker opens after threshold consecutive failed calls and rejects
+// calls until cooldown has passed. It then lets a single probe through:
+// success closes it again, failure restarts the cooldown. A threshold of 0
+// disables it.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+func (b *circuitBreaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return nil
+	}
+
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return errCircuitOpen
+	}
+
+	b.probing = true
+	return nil
+}
+
+func (b *circuitBreaker) record(healthy bool) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if healthy {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold || !b.openedAt.IsZero() {
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+)
+
+// scriptedBackend fails with the queued errors in order, then succeeds.
+// With partial set it streams a piece of the reply before failing.
+type scriptedBackend struct {
+	errs    []error
+	partial bool
+	calls   int
+}
+
+func (b *scriptedBackend) complete(ctx context.Context, req *chatRequest) (string, error) {
+	b.calls++
+	if b.partial && req.Stream != nil {
+		req.Stream("{")
+	}
+	if len(b.errs) > 0 {
+		err := b.errs[0]
+		b.errs = b.errs[1:]
+		return "", err
+	}
+	return "ok", nil
+}
+
+func testResilienceConfig() *config.AIConfig {
+	return &config.AIConfig{
+		MaxRetries:       2,
+		RetryBaseDelay:   time.Millisecond,
+		RetryMaxDelay:    5 * time.Millisecond,
+		BreakerThreshold: 3,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+func TestResilientBackendRetriesUnavailable(t *testing.T) {
+	next := &scriptedBackend{errs: []error{
+		&apiError{StatusCode: http.StatusServiceUnavailable},
+		&apiError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Millisecond},
+	}}
+	backend := newResilientBackend(next, testResilienceConfig())
+
+	reply, err := backend.complete(context.Background(), &chatRequest{})
+	if err != nil || reply != "ok" || next.calls != 3 {
+		t.Fatalf("got %q, %v after %d calls; want success after 3", reply, err, next.calls)
+	}
+}
+
+func TestResilientBackendDoesNotRetry(t *testing.T) {
+	tests := map[string]error{
+		"client error":         &apiError{StatusCode: http.StatusBadRequest},
+		"retry-after too long": &apiError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour},
+	}
+
+	for name, failure := range tests {
+		next := &scriptedBackend{errs: []error{failure, failure}}
+		backend := newResilientBackend(next, testResilienceConfig())
+
+		if _, err := backend.complete(context.Background(), &chatRequest{}); err == nil || next.calls != 1 {
+			t.Errorf("%s: got %v after %d calls, want the error after 1", name, err, next.calls)
+		}
+	}
+
+	next := &scriptedBackend{errs: []error{&apiError{StatusCode: http.StatusBadGateway}}, partial: true}
+	backend := newResilientBackend(next, testResilienceConfig())
+	streamed := &chatRequest{Stream: func(string) error { return nil }}
+	if _, err := backend.complete(context.Background(), streamed); err == nil || next.calls != 1 {
+		t.Errorf("stream that already started: got %v after %d calls, want the error after 1", err, next.calls)
+	}
+}
+
+func TestCircuitBreakerOpensAndProbes(t *testing.T) {
+	now := time.Unix(1000, 0)
+	next := &scriptedBackend{}
+	for i := 0; i < 4; i++ {
+		next.errs = append(next.errs, &apiError{StatusCode: http.StatusInternalServerError})
+	}
+
+	cfg := testResilienceConfig()
+	cfg.MaxRetries = 0
+	backend := newResilientBackend(next, cfg)
+	backend.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		backend.complete(context.Background(), &chatRequest{})
+	}
+
+	if _, err := backend.complete(context.Background(), &chatRequest{}); !errors.Is(err, errCircuitOpen) || next.calls != 3 {
+		t.Fatalf("expected open circuit without calling the provider, got %v after %d calls", err, next.calls)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := backend.complete(context.Background(), &chatRequest{}); !errors.Is(err, domain.ErrAIUnavailable) || next.calls != 4 {
+		t.Fatalf("expected a failed probe, got %v after %d calls", err, next.calls)
+	}
+	if _, err := backend.complete(context.Background(), &chatRequest{}); !errors.Is(err, errCircuitOpen) {
+		t.Fatalf("failed probe should reopen the circuit, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if reply, err := backend.complete(context.Background(), &chatRequest{}); err != nil || reply != "ok" {
+		t.Fatalf("expected successful probe, got %q, %v", reply, err)
+	}
+	if _, err := backend.complete(context.Background(), &chatRequest{}); err != nil {
+		t.Fatalf("successful probe should close the circuit, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"-3":                            0,
+		"soon":                          0,
+		"Mon, 01 Jan 2024 12:00:30 GMT": 30 * time.Second,
+	}
+
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
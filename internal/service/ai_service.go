@@ -1,163 +1,310 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"regexp"
+	"strings"
 	"time"
 
 	"gymapp/internal/config"
+	"gymapp/internal/domain"
 )
 
-type AIService struct {
-	apiKey  string
-	baseURL string
-	model   string
-	client  *http.Client
-}
+// maxStreamLine bounds a single line of a streamed reply.
+const maxStreamLine = 1 << 20
 
 type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string
+	Content string
+	Images  []ChatImage
+}
+
+// ChatImage is an image attached to a message for vision-capable models.
+type ChatImage struct {
+	MIMEType string
+	Data     []byte
 }
 
-type ChatRequest struct {
-	Model       string        `json:"model"`
-	Messages    []ChatMessage `json:"messages"`
-	Temperature float64       `json:"temperature"`
+type chatRequest struct {
+	Messages []ChatMessage
+	// Vision routes the request to the configured vision-capable model.
+	Vision bool
+	// JSONMode asks the backend to constrain output to a JSON object.
+	JSONMode bool
+	// Stream, when set, switches the backend to its streaming mode and
+	// receives each piece of the reply as it arrives.
+	Stream domain.StreamFunc
+	// SkipCache bypasses the response cache for this request.
+	SkipCache bool
+	// Usage, when set, accumulates the tokens the provider reports.
+	Usage *domain.TokenUsage
+	// Validate, when set, rejects replies that must not be cached.
+	Validate func(reply string) error
 }
 
-type ChatResponse struct {
-	Choices []struct {
-		Message ChatMessage `json:"message"`
-	} `json:"choices"`
+// chatBackend sends a conversation to a model and returns the reply text,
+// streaming it first when the request asks for it. Each supported API
+// (OpenAI-compatible, Ollama) has its own backend.
+type chatBackend interface {
+	complete(ctx context.Context, req *chatRequest) (string, error)
+}
+
+// AIService builds the prompts, the recipe and training ones from versioned
+// templates, and sends them through a chat backend.
+type AIService struct {
+	backend chatBackend
+	prompts *promptSet
+}
+
+// NewAIProvider returns the provider selected by cfg.Provider. When no
+// provider is configured, the OpenAI-compatible backend is used if an API key
+// is set and the mock otherwise. Real backends are wrapped with retries, a
+// circuit breaker and, when enabled, the response cache; cacheStore may be
+// nil to keep the cache in memory only. The prompt templates are loaded and
+// checked here so that a broken override fails at startup.
+func NewAIProvider(cfg *config.AIConfig, cacheStore domain.AICacheRepository) (domain.AIProvider, error) {
+	prompts, err := loadPrompts(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	// Streams stay open for as long as the model writes, so only the wait
+	// for the response headers is bounded; the caller's context does the rest.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = 30 * time.Second
+	streamClient := &http.Client{Transport: transport}
+
+	provider := cfg.Provider
+	if provider == "" {
+		provider = domain.AIProviderMock
+		if cfg.APIKey != "" {
+			provider = domain.AIProviderOpenAI
+		}
+	}
+
+	switch provider {
+	case domain.AIProviderOpenAI:
+		return NewAIService(wrapBackend(provider, newOpenAIBackend(cfg, client, streamClient), cfg, cacheStore), prompts), nil
+	case domain.AIProviderOllama:
+		return NewAIService(wrapBackend(provider, newOllamaBackend(cfg, client, streamClient), cfg, cacheStore), prompts), nil
+	case domain.AIProviderMock:
+		return NewMockAIProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q", provider)
+	}
 }
 
-func NewAIService(cfg *config.AIConfig) *AIService {
-	return &AIService{
-		apiKey:  cfg.APIKey,
-		baseURL: cfg.BaseURL,
-		model:   cfg.Model,
-		client:  &http.Client{Timeout: 30 * time.Second},
+// wrapBackend puts retries and the circuit breaker around a backend and the
+// cache in front of them, so cache hits never count against the provider.
+func wrapBackend(provider string, backend chatBackend, cfg *config.AIConfig, cacheStore domain.AICacheRepository) chatBackend {
+	backend = newResilientBackend(backend, cfg)
+	if cfg.CacheEnabled {
+		backend = newCachingBackend(backend, provider, cfg, cacheStore)
 	}
+	return backend
+}
+
+func NewAIService(backend chatBackend, prompts *promptSet) *AIService {
+	return &AIService{backend: backend, prompts: prompts}
 }
 
-func (s *AIService) GenerateRecipes(ctx context.Context, ingredients string) (string, error) {
-	if s.apiKey == "" {
-		return s.mockRecipeGeneration(ingredients), nil
+// GenerateRecipes renders the user's version of the recipes prompt and
+// records it on input.
+func (s *AIService) GenerateRecipes(ctx context.Context, input *domain.RecipeInput) (string, error) {
+	prompt, version, err := s.prompts.render(promptRecipes, input.UserID, input)
+	if err != nil {
+		return "", err
 	}
+	input.PromptVersion = version
 
-	prompt := fmt.Sprintf(`You are a helpful nutritionist. Given these ingredients: %s
-	
-Provide 3 healthy recipe ideas with:
-1. Recipe name
-2. Ingredients needed (with quantities)
-3. Estimated calories
-4. Preparation time
-5. Health benefits
+	return s.callChatAPI(ctx, prompt, chatRequest{
+		JSONMode:  true,
+		Stream:    input.Stream,
+		SkipCache: input.SkipCache,
+		Usage:     input.Usage,
+		Validate:  input.Validate,
+	})
+}
 
-Format as JSON with array of recipes.`, ingredients)
+// GenerateTrainingPlan renders the user's version of the training plan
+// prompt and records it on input.
+func (s *AIService) GenerateTrainingPlan(ctx context.Context, input *domain.TrainingPlanInput) (string, error) {
+	prompt, version, err := s.prompts.render(promptTrainingPlan, input.UserID, input)
+	if err != nil {
+		return "", err
+	}
+	input.PromptVersion = version
 
-	return s.callChatAPI(ctx, prompt)
+	return s.callChatAPI(ctx, prompt, chatRequest{
+		JSONMode:  true,
+		Stream:    input.Stream,
+		SkipCache: input.SkipCache,
+		Usage:     input.Usage,
+		Validate:  input.Validate,
+	})
 }
 
-func (s *AIService) GenerateTrainingPlan(ctx context.Context, weight, targetWeight, height, availableDays int) (string, error) {
-	if s.apiKey == "" {
-		return s.mockTrainingPlanGeneration(weight, targetWeight, height, availableDays), nil
+func (s *AIService) GenerateMealPlan(ctx context.Context, input *domain.MealPlanInput) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Daily calorie target: %d kcal\n", input.CalorieTarget)
+	if t := input.Targets; t != nil {
+		fmt.Fprintf(&b, "Daily macro targets: %dg protein, %dg carbs, %dg fat\n", t.ProteinGrams, t.CarbsGrams, t.FatGrams)
+	}
+	if input.Goal != "" {
+		fmt.Fprintf(&b, "Fitness goal: %s\n", input.Goal)
+	}
+	if input.Preferences != "" {
+		fmt.Fprintf(&b, "Dietary preferences: %s\n", input.Preferences)
+	}
+	b.WriteString(dietSection(input.Diet))
+	if len(input.Avoid) > 0 {
+		fmt.Fprintf(&b, "Do not repeat these dishes: %s\n", strings.Join(input.Avoid, "; "))
 	}
 
-	prompt := fmt.Sprintf(`You are an expert fitness coach. Create a personalized training plan with:
-Current: Weight=%dkg, Height=%dcm, Available days per week=%d
-Goal: Target weight=%dkg
+	b.WriteString("\nPlan exactly these meals, each sized to about the given calories:\n")
+	for _, slot := range input.Slots {
+		fmt.Fprintf(&b, "%s %s: %d kcal\n", slot.Day, slot.Meal, slot.Calories)
+	}
 
-Provide:
-1. Weekly workout schedule
-2. Exercise type and duration for each day
-3. Nutrition guidance
-4. Recovery recommendations
-5. Expected timeline
+	prompt := fmt.Sprintf(`You are a helpful nutritionist planning a week of meals for one person.
+%s
+Respond with a single JSON object matching this schema:
+{
+  "meals": [
+    {
+      "day": "<monday|tuesday|wednesday|thursday|friday|saturday|sunday>",
+      "meal": "<breakfast|lunch|dinner|snack>",
+      "name": "<dish name>",
+      "servings": 1,
+      "prep_time_minutes": <integer>,
+      "calories": <integer, per serving>,
+      "protein_g": <number, per serving>,
+      "carbs_g": <number, per serving>,
+      "fat_g": <number, per serving>,
+      "ingredients": [
+        {"name": "<ingredient>", "quantity": <number>, "unit": "<g|ml|pcs|tbsp|tsp|cup>"}
+      ],
+      "steps": ["<preparation step>"]
+    }
+  ]
+}
 
-Format as JSON.`, weight, height, availableDays, targetWeight)
+Vary the dishes across the week.`, b.String())
 
-	return s.callChatAPI(ctx, prompt)
+	return s.callChatAPI(ctx, prompt, chatRequest{
+		JSONMode:  true,
+		Stream:    input.Stream,
+		SkipCache: input.SkipCache,
+		Usage:     input.Usage,
+		Validate:  input.Validate,
+	})
 }
 
-func (s *AIService) callChatAPI(ctx context.Context, prompt string) (string, error) {
-	req := ChatRequest{
-		Model: s.model,
+// dietSection states the user's restrictions, allergens and dislikes.
+// Allergens are phrased as a hard rule since dishes containing them are
+// rejected afterwards.
+func dietSection(diet domain.DietaryPreferences) string {
+	var b strings.Builder
+	if len(diet.Restrictions) > 0 {
+		fmt.Fprintf(&b, "Follow these dietary restrictions: %s\n",
+			strings.ReplaceAll(strings.Join(diet.Restrictions, ", "), "_", "-"))
+	}
+	if len(diet.Allergens) > 0 {
+		fmt.Fprintf(&b, "The user is allergic to: %s. Never use these or ingredients derived from them.\n",
+			strings.Join(diet.Allergens, ", "))
+	}
+	if len(diet.Dislikes) > 0 {
+		fmt.Fprintf(&b, "Avoid these disliked foods: %s\n", strings.Join(diet.Dislikes, ", "))
+	}
+	return b.String()
+}
+
+// catalogSection lists the exercises a plan may use, one "id: name" line each
+// with the details the model needs to pick sensibly.
+func catalogSection(exercises []*domain.Exercise) string {
+	if len(exercises) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nUse only exercises from this catalog and set exercise_id to the catalog id:\n")
+	for _, ex := range exercises {
+		fmt.Fprintf(&b, "%d: %s (%s, %s, %s; %s)\n", ex.ID, ex.Name, ex.MovementPattern, ex.Equipment,
+			ex.Difficulty, strings.Join(ex.PrimaryMuscles, ", "))
+	}
+
+	return b.String()
+}
+
+func (s *AIService) DetectIngredients(ctx context.Context, imageData []byte, mimeType string, usage *domain.TokenUsage) ([]string, error) {
+	prompt := `List the food ingredients visible in this image.
+Respond with a comma-separated list of ingredient names only, no quantities and no other text.
+If no food is visible, respond with an empty line.`
+
+	reply, err := s.backend.complete(ctx, &chatRequest{
 		Messages: []ChatMessage{
 			{
 				Role:    "user",
 				Content: prompt,
+				Images:  []ChatImage{{MIMEType: mimeType, Data: imageData}},
 			},
 		},
-		Temperature: 0.7,
-	}
-
-	body, err := json.Marshal(req)
+		Vision: true,
+		Usage:  usage,
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST",
-		s.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+s.apiKey)
+	return parseIngredientList(reply), nil
+}
 
-	resp, err := s.client.Do(httpReq)
-	if err != nil {
-		return "", fmt.Errorf("api request failed: %w", err)
+// callChatAPI sends prompt as a single user message with the options set in
+// req.
+func (s *AIService) callChatAPI(ctx context.Context, prompt string, req chatRequest) (string, error) {
+	req.Messages = []ChatMessage{
+		{
+			Role:    "user",
+			Content: prompt,
+		},
 	}
-	defer resp.Body.Close()
+	return s.backend.complete(ctx, &req)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		data, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("api error: status %d, body: %s", resp.StatusCode, string(data))
+// extractJSONObject returns the outermost {...} in a model reply, dropping
+// markdown code fences or prose some models wrap around JSON.
+func extractJSONObject(reply string) string {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start == -1 || end < start {
+		return reply
 	}
+	return reply[start : end+1]
+}
 
-	var chatResp ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
+var listMarker = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s*`)
 
-	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
-	}
+// parseIngredientList splits a model reply into distinct ingredient names,
+// tolerating newline- or bullet-separated output as well as commas.
+func parseIngredientList(reply string) []string {
+	fields := strings.FieldsFunc(reply, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ';'
+	})
 
-	return chatResp.Choices[0].Message.Content, nil
-}
+	seen := make(map[string]bool)
+	var ingredients []string
+	for _, field := range fields {
+		name := listMarker.ReplaceAllString(field, "")
+		name = strings.ToLower(strings.TrimSpace(strings.TrimRight(name, ". ")))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		ingredients = append(ingredients, name)
+	}
 
-func (s *AIService) mockRecipeGeneration(ingredients string) string {
-	return fmt.Sprintf(`{
-  "recipes": [
-    {
-      "name": "Healthy Salad Bowl with %s",
-      "calories": 350,
-      "prep_time": "15 minutes",
-      "benefits": "Rich in vitamins and fiber"
-    }
-  ]
-}`, ingredients)
-}
-
-func (s *AIService) mockTrainingPlanGeneration(weight, targetWeight, height, availableDays int) string {
-	return fmt.Sprintf(`{
-  "plan": {
-    "duration_weeks": 12,
-    "weekly_schedule": {
-      "monday": "Cardio 30min, Strength 20min",
-      "wednesday": "Strength training 45min",
-      "friday": "Mixed cardio and agility"
-    },
-    "nutrition": "Caloric deficit of 500-750 kcal/day",
-    "expected_weight_loss": %d
-  }
-}`, weight-targetWeight)
+	return ingredients
 }
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gymapp/internal/domain"
+)
+
+func TestParseIngredientList(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  []string
+	}{
+		{"Tomato, basil, Mozzarella", []string{"tomato", "basil", "mozzarella"}},
+		{"1. eggs\n2. spinach\n- eggs\n", []string{"eggs", "spinach"}},
+		{"* 2% milk.", []string{"2% milk"}},
+		{"\n", nil},
+	}
+
+	for _, tt := range tests {
+		got := parseIngredientList(tt.reply)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseIngredientList(%q) = %v, want %v", tt.reply, got, tt.want)
+		}
+	}
+}
+
+func TestMockDetectIngredientsIsDeterministic(t *testing.T) {
+	mock := NewMockAIProvider()
+	image := []byte("\x89PNG\r\n\x1a\nfake image bytes")
+
+	first, err := mock.DetectIngredients(context.Background(), image, "image/png", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, _ := mock.DetectIngredients(context.Background(), image, "image/png", nil)
+	if len(first) == 0 || !reflect.DeepEqual(first, second) {
+		t.Errorf("expected identical non-empty results, got %v and %v", first, second)
+	}
+}
+
+func TestReadStreams(t *testing.T) {
+	openAI := `data: {"choices":[{"delta":{"role":"assistant"}}]}
+
+data: {"choices":[{"delta":{"content":"{\"recipes\""}}]}
+
+: keep-alive
+data: {"choices":[{"delta":{"content":": []}"}}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":5}}
+
+data: [DONE]
+
+`
+	ollama := `{"message":{"role":"assistant","content":"{\"recipes\""},"done":false}
+{"message":{"role":"assistant","content":": []}"},"done":false}
+{"message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":12,"eval_count":5}
+`
+
+	readers := map[string]func(*domain.TokenUsage) (string, []string, error){
+		"openai": func(usage *domain.TokenUsage) (string, []string, error) {
+			var deltas []string
+			reply, err := readOpenAIStream(strings.NewReader(openAI), func(d string) error {
+				deltas = append(deltas, d)
+				return nil
+			}, usage)
+			return reply, deltas, err
+		},
+		"ollama": func(usage *domain.TokenUsage) (string, []string, error) {
+			var deltas []string
+			reply, err := readOllamaStream(strings.NewReader(ollama), func(d string) error {
+				deltas = append(deltas, d)
+				return nil
+			}, usage)
+			return reply, deltas, err
+		},
+	}
+
+	for name, read := range readers {
+		var usage domain.TokenUsage
+		reply, deltas, err := read(&usage)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if reply != `{"recipes": []}` {
+			t.Errorf("%s: reply = %q", name, reply)
+		}
+		if want := []string{`{"recipes"`, `: []}`}; !reflect.DeepEqual(deltas, want) {
+			t.Errorf("%s: deltas = %q, want %q", name, deltas, want)
+		}
+		if want := (domain.TokenUsage{PromptTokens: 12, CompletionTokens: 5}); usage != want {
+			t.Errorf("%s: usage = %+v, want %+v", name, usage, want)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -71,6 +73,18 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*domai
 }
 
 func (s *AuthService) GenerateTokens(userID int64) (string, string, error) {
+	familyID, err := newTokenID()
+	if err != nil {
+		return "", "", err
+	}
+
+	return s.issueTokens(userID, familyID, 0)
+}
+
+// issueTokens signs a fresh access/refresh pair and stores the refresh token
+// as a member of familyID. parentID is the token being rotated, or 0 for a
+// new login.
+func (s *AuthService) issueTokens(userID int64, familyID string, parentID int64) (string, string, error) {
 	now := time.Now()
 
 	accessClaims := jwt.MapClaims{
@@ -85,8 +99,14 @@ func (s *AuthService) GenerateTokens(userID int64) (string, string, error) {
 		return "", "", fmt.Errorf("failed to sign access token: %w", err)
 	}
 
+	jti, err := newTokenID()
+	if err != nil {
+		return "", "", err
+	}
+
 	refreshClaims := jwt.MapClaims{
 		"sub": userID,
+		"jti": jti,
 		"iat": now.Unix(),
 		"exp": now.Add(s.cfg.RefreshTokenTTL).Unix(),
 	}
@@ -98,8 +118,10 @@ func (s *AuthService) GenerateTokens(userID int64) (string, string, error) {
 	}
 
 	rt := &domain.RefreshToken{
-		UserID: userID,
-		Token:  refreshTokenStr,
+		UserID:   userID,
+		Token:    refreshTokenStr,
+		FamilyID: familyID,
+		ParentID: parentID,
 	}
 	if err := s.refreshTokenRepo.Create(context.Background(), rt); err != nil {
 		return "", "", fmt.Errorf("failed to store refresh token: %w", err)
@@ -132,7 +154,10 @@ func (s *AuthService) ValidateToken(token string) (int64, error) {
 	return int64(userID), nil
 }
 
-func (s *AuthService) RefreshAccessToken(refreshToken string) (string, error) {
+// RefreshAccessToken exchanges a refresh token for a new token pair. The
+// presented token is marked as rotated; presenting it again is treated as
+// theft and revokes every token in its family.
+func (s *AuthService) RefreshAccessToken(refreshToken string) (string, string, error) {
 	claims := &jwt.MapClaims{}
 
 	_, err := jwt.ParseWithClaims(refreshToken, claims, func(token *jwt.Token) (interface{}, error) {
@@ -140,39 +165,84 @@ func (s *AuthService) RefreshAccessToken(refreshToken string) (string, error) {
 	})
 
 	if err != nil {
-		return "", fmt.Errorf("invalid refresh token: %w", err)
+		return "", "", fmt.Errorf("invalid refresh token: %w", err)
 	}
 
 	sub, ok := (*claims)["sub"]
 	if !ok {
-		return "", fmt.Errorf("invalid token: missing sub")
+		return "", "", fmt.Errorf("invalid token: missing sub")
 	}
 
 	userID, ok := sub.(float64)
 	if !ok {
-		return "", fmt.Errorf("invalid token: sub is not a number")
+		return "", "", fmt.Errorf("invalid token: sub is not a number")
 	}
 
-	rt, err := s.refreshTokenRepo.GetByToken(context.Background(), refreshToken)
+	ctx := context.Background()
+
+	rt, err := s.refreshTokenRepo.GetByToken(ctx, refreshToken)
 	if err != nil {
-		return "", fmt.Errorf("refresh token not found: %w", err)
+		return "", "", fmt.Errorf("refresh token not found: %w", err)
+	}
+
+	if rt.UserID != int64(userID) {
+		return "", "", fmt.Errorf("invalid token: user mismatch")
+	}
+
+	if rt.RotatedAt != 0 {
+		return "", "", s.revokeFamily(ctx, rt)
 	}
 
 	if rt.ExpiresAt < time.Now().Unix() {
-		return "", fmt.Errorf("refresh token expired")
+		return "", "", fmt.Errorf("refresh token expired")
 	}
 
-	newClaims := jwt.MapClaims{
-		"sub": int64(userID),
-		"iat": time.Now().Unix(),
-		"exp": time.Now().Add(s.cfg.AccessTokenTTL).Unix(),
+	if err := s.refreshTokenRepo.MarkRotated(ctx, rt.ID); err != nil {
+		// Lost a race with another refresh using the same token.
+		return "", "", s.revokeFamily(ctx, rt)
 	}
 
-	newToken := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims)
-	newAccessToken, err := newToken.SignedString([]byte(s.cfg.Secret))
+	return s.issueTokens(rt.UserID, rt.FamilyID, rt.ID)
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, rt *domain.RefreshToken) error {
+	if err := s.refreshTokenRepo.DeleteFamily(ctx, rt.UserID, rt.FamilyID); err != nil {
+		return fmt.Errorf("refresh token reuse detected, failed to revoke family: %w", err)
+	}
+
+	return fmt.Errorf("refresh token reuse detected")
+}
+
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return fmt.Errorf("refresh token is required")
+	}
+
+	rt, err := s.refreshTokenRepo.GetByToken(ctx, refreshToken)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign new token: %w", err)
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	if err := s.refreshTokenRepo.DeleteFamily(ctx, rt.UserID, rt.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) LogoutAll(ctx context.Context, userID int64) error {
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
 	}
 
-	return newAccessToken, nil
+	return hex.EncodeToString(b), nil
 }
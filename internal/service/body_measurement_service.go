@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"gymapp/internal/domain"
+)
+
+const (
+	defaultMeasurementRange = 90 * 24 * time.Hour
+	trendWindow             = 30 * 24 * time.Hour
+	maxBodyFatPct           = 75
+	minCircumferenceCm      = 10
+	maxCircumferenceCm      = 300
+)
+
+type BodyMeasurementService struct {
+	measurementRepo domain.BodyMeasurementRepository
+}
+
+func NewBodyMeasurementService(measurementRepo domain.BodyMeasurementRepository) *BodyMeasurementService {
+	return &BodyMeasurementService{measurementRepo: measurementRepo}
+}
+
+// Log stores a measurement. When it is the user's most recent weight, the
+// repository updates the profile weight to match so existing consumers stay
+// current.
+func (s *BodyMeasurementService) Log(ctx context.Context, userID int64, m *domain.BodyMeasurement) (*domain.BodyMeasurement, error) {
+	now := time.Now()
+	if m.MeasuredAt == 0 {
+		m.MeasuredAt = now.Unix()
+	}
+	if m.MeasuredAt > now.Add(24*time.Hour).Unix() {
+		return nil, fmt.Errorf("%w: measured_at cannot be in the future", domain.ErrInvalidInput)
+	}
+
+	if err := validateMeasurement(m); err != nil {
+		return nil, err
+	}
+
+	m.UserID = userID
+	if err := s.measurementRepo.Create(ctx, m); err != nil {
+		return nil, fmt.Errorf("failed to save measurement: %w", err)
+	}
+
+	return m, nil
+}
+
+// List returns measurements between from and to (unix seconds, defaulting
+// to the last 90 days) together with the weight trend over that range.
+func (s *BodyMeasurementService) List(ctx context.Context, userID int64, from, to int64) ([]*domain.BodyMeasurement, *domain.WeightTrend, error) {
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if from == 0 {
+		from = to - int64(defaultMeasurementRange.Seconds())
+	}
+	if from > to {
+		return nil, nil, fmt.Errorf("%w: from must not be after to", domain.ErrInvalidInput)
+	}
+
+	measurements, err := s.measurementRepo.GetRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return measurements, computeWeightTrend(measurements), nil
+}
+
+func (s *BodyMeasurementService) Delete(ctx context.Context, userID, id int64) error {
+	return s.measurementRepo.Delete(ctx, id, userID)
+}
+
+func validateMeasurement(m *domain.BodyMeasurement) error {
+	if m.WeightKg == 0 && m.BodyFatPct == 0 && m.WaistCm == 0 && m.ChestCm == 0 &&
+		m.HipsCm == 0 && m.ArmCm == 0 && m.ThighCm == 0 {
+		return fmt.Errorf("%w: at least one measurement is required", domain.ErrInvalidInput)
+	}
+
+	if m.WeightKg != 0 && (m.WeightKg < minWeight || m.WeightKg > maxWeight) {
+		return fmt.Errorf("%w: weight_kg must be between %d and %d", domain.ErrInvalidInput, minWeight, maxWeight)
+	}
+
+	if m.BodyFatPct != 0 && (m.BodyFatPct < 1 || m.BodyFatPct > maxBodyFatPct) {
+		return fmt.Errorf("%w: body_fat_pct must be between 1 and %d", domain.ErrInvalidInput, maxBodyFatPct)
+	}
+
+	circumferences := []struct {
+		name  string
+		value float64
+	}{
+		{"waist_cm", m.WaistCm},
+		{"chest_cm", m.ChestCm},
+		{"hips_cm", m.HipsCm},
+		{"arm_cm", m.ArmCm},
+		{"thigh_cm", m.ThighCm},
+	}
+	for _, c := range circumferences {
+		if c.value != 0 && (c.value < minCircumferenceCm || c.value > maxCircumferenceCm) {
+			return fmt.Errorf("%w: %s must be between %d and %d", domain.ErrInvalidInput, c.name, minCircumferenceCm, maxCircumferenceCm)
+		}
+	}
+
+	return nil
+}
+
+// computeWeightTrend fits a least-squares line through the weight samples.
+// It returns nil when fewer than two weights were recorded.
+func computeWeightTrend(measurements []*domain.BodyMeasurement) *domain.WeightTrend {
+	var samples []*domain.BodyMeasurement
+	for _, m := range measurements {
+		if m.WeightKg > 0 {
+			samples = append(samples, m)
+		}
+	}
+
+	if len(samples) < 2 {
+		return nil
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	origin := float64(first.MeasuredAt)
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, m := range samples {
+		x := (float64(m.MeasuredAt) - origin) / (7 * 24 * 3600)
+		sumX += x
+		sumY += m.WeightKg
+		sumXY += x * m.WeightKg
+		sumXX += x * x
+	}
+
+	n := float64(len(samples))
+	slope := 0.0
+	if denom := n*sumXX - sumX*sumX; denom != 0 {
+		slope = (n*sumXY - sumX*sumY) / denom
+	}
+
+	return &domain.WeightTrend{
+		Samples:   len(samples),
+		DaysSpan:  int((last.MeasuredAt - first.MeasuredAt) / (24 * 3600)),
+		ChangeKg:  math.Round((last.WeightKg-first.WeightKg)*100) / 100,
+		KgPerWeek: math.Round(slope*100) / 100,
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gymapp/internal/domain"
+)
+
+const (
+	maxDietItems   = 20
+	maxDietItemLen = 50
+)
+
+// foodGroup is a set of ingredient terms. A term matches whole words, with
+// hyphens splitting words and singular and plural forms of its last word
+// treated alike, unless it is preceded by one of the except words (so
+// "coconut milk" is not dairy).
+type foodGroup struct {
+	terms  []string
+	except []string
+}
+
+var foodGroups = map[string]foodGroup{
+	"meat": {terms: []string{
+		"meat", "chicken", "beef", "pork", "lamb", "mutton", "turkey", "duck", "veal", "venison",
+		"bacon", "ham", "sausage", "steak", "mince", "prosciutto", "salami", "chorizo", "pepperoni",
+		"gelatin", "lard",
+	}},
+	"pork": {terms: []string{"pork", "bacon", "ham", "prosciutto", "pancetta", "lard", "chorizo", "salami", "pepperoni"}},
+	"fish": {terms: []string{
+		"fish", "salmon", "tuna", "cod", "tilapia", "trout", "sardine", "anchovy", "anchovies", "mackerel",
+		"halibut", "haddock", "sea bass", "fish sauce",
+	}},
+	"shellfish": {terms: []string{
+		"shellfish", "shrimp", "prawn", "crab", "lobster", "mussel", "clam", "oyster", "scallop", "squid", "octopus",
+	}},
+	"dairy": {
+		terms: []string{
+			"milk", "cheese", "butter", "yogurt", "yoghurt", "cream", "whey", "ghee", "casein", "kefir",
+			"parmesan", "mozzarella", "feta", "cheddar", "ricotta", "cottage cheese", "cream cheese",
+		},
+		except: []string{"coconut", "almond", "oat", "soy", "rice", "cashew", "peanut", "nut", "cocoa", "vegan"},
+	},
+	"egg":    {terms: []string{"egg", "mayonnaise", "mayo", "meringue"}, except: []string{"vegan"}},
+	"honey":  {terms: []string{"honey"}},
+	"peanut": {terms: []string{"peanut"}},
+	"tree_nut": {terms: []string{
+		"nut", "almond", "walnut", "cashew", "pecan", "pistachio", "hazelnut", "macadamia", "brazil nut", "pine nut",
+	}},
+	"gluten": {
+		terms: []string{
+			"wheat", "flour", "bread", "pasta", "spaghetti", "noodle", "couscous", "barley", "rye", "seitan",
+			"bulgur", "semolina", "tortilla", "breadcrumb", "cracker",
+		},
+		except: []string{"rice", "corn", "almond", "coconut", "chickpea", "buckwheat", "gluten free"},
+	},
+	"soy":     {terms: []string{"soy", "soya", "tofu", "tempeh", "edamame", "miso"}},
+	"sesame":  {terms: []string{"sesame", "tahini"}},
+	"alcohol": {terms: []string{"wine", "beer", "rum", "vodka", "brandy", "sake", "mirin"}},
+}
+
+// allergenAliases maps common allergen names to a food group.
+var allergenAliases = map[string]string{
+	"peanut": "peanut", "peanuts": "peanut",
+	"nut": "tree_nut", "nuts": "tree_nut", "tree nut": "tree_nut", "tree nuts": "tree_nut", "tree_nut": "tree_nut",
+	"milk": "dairy", "dairy": "dairy", "lactose": "dairy",
+	"egg": "egg", "eggs": "egg",
+	"fish":      "fish",
+	"shellfish": "shellfish", "crustacean": "shellfish", "crustaceans": "shellfish",
+	"gluten": "gluten", "wheat": "gluten",
+	"soy": "soy", "soya": "soy",
+	"sesame": "sesame",
+}
+
+// restrictionGroups lists the food groups each dietary restriction rules
+// out. Restrictions without groups, such as keto, only shape the prompt.
+var restrictionGroups = map[string][]string{
+	domain.DietVegan:       {"meat", "fish", "shellfish", "dairy", "egg", "honey"},
+	domain.DietVegetarian:  {"meat", "fish", "shellfish"},
+	domain.DietPescatarian: {"meat"},
+	domain.DietGlutenFree:  {"gluten"},
+	domain.DietDairyFree:   {"dairy"},
+	domain.DietHalal:       {"pork", "alcohol"},
+	domain.DietKosher:      {"pork", "shellfish"},
+	domain.DietKeto:        nil,
+	domain.DietLowCarb:     nil,
+}
+
+// normalizeDietList lowercases, trims and de-duplicates a preference list.
+func normalizeDietList(field string, items []string) ([]string, error) {
+	if len(items) > maxDietItems {
+		return nil, fmt.Errorf("%w: %s must have at most %d items", domain.ErrInvalidInput, field, maxDietItems)
+	}
+
+	out := []string{}
+	for _, item := range items {
+		item = strings.Join(strings.Fields(strings.ToLower(item)), " ")
+		if item == "" || slices.Contains(out, item) {
+			continue
+		}
+		if len(item) > maxDietItemLen {
+			return nil, fmt.Errorf("%w: %s entries must be at most %d characters", domain.ErrInvalidInput, field, maxDietItemLen)
+		}
+		out = append(out, item)
+	}
+
+	return out, nil
+}
+
+// dietRule matches one preference against dish text.
+type dietRule struct {
+	label string
+	group foodGroup
+}
+
+// dietRules checks dishes against a user's dietary preferences.
+type dietRules struct {
+	allergens []dietRule
+	flagged   []dietRule
+}
+
+func newDietRules(diet domain.DietaryPreferences) *dietRules {
+	if diet.IsEmpty() {
+		return nil
+	}
+
+	r := &dietRules{}
+	for _, allergen := range diet.Allergens {
+		group, ok := foodGroups[allergenAliases[strings.Join(dietWords(allergen), " ")]]
+		if !ok {
+			group = foodGroup{terms: []string{allergen}}
+		}
+		r.allergens = append(r.allergens, dietRule{label: allergen, group: group})
+	}
+
+	for _, restriction := range diet.Restrictions {
+		for _, name := range restrictionGroups[restriction] {
+			r.flagged = append(r.flagged, dietRule{label: restriction, group: foodGroups[name]})
+		}
+	}
+
+	for _, dislike := range diet.Dislikes {
+		r.flagged = append(r.flagged, dietRule{label: "disliked", group: foodGroup{terms: []string{dislike}}})
+	}
+
+	return r
+}
+
+// screen sets the dish flags for restriction and dislike conflicts, such
+// as "contains bacon (vegetarian)", and returns the allergens found in the
+// dish. Dishes with allergens must be rejected.
+func (r *dietRules) screen(dish *domain.Dish) []string {
+	dish.Flags = nil
+	if r == nil {
+		return nil
+	}
+
+	texts := make([]string, 0, len(dish.Ingredients)+1)
+	texts = append(texts, dish.Name)
+	for _, ing := range dish.Ingredients {
+		texts = append(texts, ing.Name)
+	}
+
+	var allergens []string
+	for _, rule := range r.allergens {
+		if rule.group.find(texts) != "" {
+			allergens = appendUnique(allergens, rule.label)
+		}
+	}
+
+	for _, rule := range r.flagged {
+		if term := rule.group.find(texts); term != "" {
+			dish.Flags = appendUnique(dish.Flags, fmt.Sprintf("contains %s (%s)", term, rule.label))
+		}
+	}
+
+	return allergens
+}
+
+// screenDishes drops dishes that contain one of the user's allergens and
+// flags the rest. It fails when every dish was rejected.
+func screenDishes(diet domain.DietaryPreferences, dishes []domain.Dish) ([]domain.Dish, error) {
+	rules := newDietRules(diet)
+
+	var rejected []string
+	kept := make([]domain.Dish, 0, len(dishes))
+	for _, dish := range dishes {
+		if allergens := rules.screen(&dish); len(allergens) > 0 {
+			rejected = appendUnique(rejected, allergens...)
+			continue
+		}
+		kept = append(kept, dish)
+	}
+
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("every suggestion contained a declared allergen (%s)", strings.Join(rejected, ", "))
+	}
+
+	return kept, nil
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// dietWords splits text into lowercase words at anything but a letter, so
+// "Sheep's-milk" and "sheep's milk" give the same words.
+func dietWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// find returns the first group term present in any of texts. Terms and
+// texts are split into words the same way.
+func (g foodGroup) find(texts []string) string {
+	for _, text := range texts {
+		words := dietWords(text)
+		for _, term := range g.terms {
+			if g.matchAt(words, dietWords(term)) {
+				return term
+			}
+		}
+	}
+	return ""
+}
+
+func (g foodGroup) matchAt(words, term []string) bool {
+	for i := 0; i+len(term) <= len(words); i++ {
+		if g.excepted(words[:i]) {
+			continue
+		}
+
+		matched := true
+		for j, t := range term {
+			w := words[i+j]
+			if w == t || (j == len(term)-1 && samePlural(w, t)) {
+				continue
+			}
+			matched = false
+			break
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// excepted reports whether the words before a match end with an except
+// phrase.
+func (g foodGroup) excepted(before []string) bool {
+	for _, e := range g.except {
+		phrase := dietWords(e)
+		if len(phrase) <= len(before) && slices.Equal(before[len(before)-len(phrase):], phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// samePlural reports whether a and b differ only by a plural "s" or "es".
+func samePlural(a, b string) bool {
+	return a == b+"s" || a == b+"es" || b == a+"s" || b == a+"es"
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"gymapp/internal/domain"
+)
+
+func testDish(name string, ingredients ...string) domain.Dish {
+	dish := domain.Dish{Name: name}
+	for _, ing := range ingredients {
+		dish.Ingredients = append(dish.Ingredients, domain.DishIngredient{Name: ing})
+	}
+	return dish
+}
+
+func TestScreenDishesRejectsAllergensAndFlagsConflicts(t *testing.T) {
+	diet := domain.DietaryPreferences{
+		Restrictions: []string{domain.DietVegetarian},
+		Allergens:    []string{"peanuts", "milk"},
+		Dislikes:     []string{"mushrooms"},
+	}
+
+	dishes, err := screenDishes(diet, []domain.Dish{
+		testDish("Satay Noodles", "rice noodles", "peanut butter"),
+		testDish("Creamy Pasta", "pasta", "heavy cream"),
+		testDish("Thai Curry", "coconut milk", "chicken thigh", "mushroom"),
+		testDish("Lentil Soup", "red lentils", "carrots"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(dishes) != 2 || dishes[0].Name != "Thai Curry" || dishes[1].Name != "Lentil Soup" {
+		t.Fatalf("unexpected dishes: %+v", dishes)
+	}
+
+	wantFlags := []string{"contains chicken (vegetarian)", "contains mushrooms (disliked)"}
+	if !reflect.DeepEqual(dishes[0].Flags, wantFlags) {
+		t.Errorf("flags = %v, want %v", dishes[0].Flags, wantFlags)
+	}
+	if dishes[1].Flags != nil {
+		t.Errorf("expected no flags, got %v", dishes[1].Flags)
+	}
+
+	_, err = screenDishes(diet, []domain.Dish{testDish("PB Toast", "bread", "peanuts")})
+	if err == nil {
+		t.Error("expected error when every dish contains an allergen")
+	}
+}
+
+func TestScreenDishesMatchesGenericAndHyphenatedTerms(t *testing.T) {
+	diet := domain.DietaryPreferences{
+		Restrictions: []string{domain.DietGlutenFree},
+		Allergens:    []string{"tree nuts", "peanut"},
+	}
+
+	dishes, err := screenDishes(diet, []domain.Dish{
+		testDish("Trail Mix", "mixed nuts", "raisins"),
+		testDish("Satay", "peanut-butter", "chicken"),
+		testDish("Walnut-Crusted Salmon", "salmon fillet"),
+		testDish("Pumpkin Bowl", "gluten-free pasta", "coconut cream", "butternut squash"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(dishes) != 1 || dishes[0].Name != "Pumpkin Bowl" {
+		t.Fatalf("expected only the nut-free dish to be kept, got %+v", dishes)
+	}
+	if dishes[0].Flags != nil {
+		t.Errorf("expected gluten-free pasta not to be flagged, got %v", dishes[0].Flags)
+	}
+}
+
+func TestScreenDishesMatchesPunctuatedEntries(t *testing.T) {
+	diet := domain.DietaryPreferences{
+		Allergens: []string{"tree-nuts", "sesame-seeds", "sheep's milk"},
+		Dislikes:  []string{"bok-choy"},
+	}
+
+	dishes, err := screenDishes(diet, []domain.Dish{
+		testDish("Nut Clusters", "tree nuts", "honey"),
+		testDish("Sesame Noodles", "noodles", "sesame seeds"),
+		testDish("Feta Salad", "sheep's milk feta", "cucumber"),
+		testDish("Stir Fry", "bok choy", "tofu"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(dishes) != 1 || dishes[0].Name != "Stir Fry" {
+		t.Fatalf("expected only the allergen-free dish to be kept, got %+v", dishes)
+	}
+	if len(dishes[0].Flags) != 1 || dishes[0].Flags[0] != "contains bok-choy (disliked)" {
+		t.Errorf("expected bok choy to be flagged, got %v", dishes[0].Flags)
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gymapp/internal/domain"
+)
+
+type ExerciseService struct {
+	exerciseRepo domain.ExerciseRepository
+}
+
+func NewExerciseService(exerciseRepo domain.ExerciseRepository) *ExerciseService {
+	return &ExerciseService{exerciseRepo: exerciseRepo}
+}
+
+// Search normalizes the filter values to the lower-case form the catalog is
+// seeded with before querying.
+func (s *ExerciseService) Search(ctx context.Context, filter *domain.ExerciseFilter) ([]*domain.Exercise, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Muscle = strings.ToLower(strings.TrimSpace(filter.Muscle))
+	filter.Equipment = strings.ToLower(strings.TrimSpace(filter.Equipment))
+	filter.MovementPattern = strings.ToLower(strings.TrimSpace(filter.MovementPattern))
+	filter.Difficulty = strings.ToLower(strings.TrimSpace(filter.Difficulty))
+
+	switch filter.Difficulty {
+	case "", domain.DifficultyBeginner, domain.DifficultyIntermediate, domain.DifficultyAdvanced:
+	default:
+		return nil, fmt.Errorf("%w: difficulty must be beginner, intermediate or advanced", domain.ErrInvalidInput)
+	}
+
+	return s.exerciseRepo.Search(ctx, filter)
+}
+
+func (s *ExerciseService) Get(ctx context.Context, id int64) (*domain.Exercise, error) {
+	return s.exerciseRepo.GetByID(ctx, id)
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gymapp/internal/domain"
+)
+
+const (
+	diaryDateLayout    = "2006-01-02"
+	defaultSummaryDays = 7
+	maxSummaryDays     = 31
+	maxEntryServings   = 20
+	maxEntryCalories   = 10000
+	maxEntryMacroGrams = 1000
+)
+
+type FoodDiaryService struct {
+	diaryRepo  domain.FoodDiaryRepository
+	recipeRepo domain.RecipeRepository
+	nutrition  domain.NutritionService
+}
+
+func NewFoodDiaryService(
+	diaryRepo domain.FoodDiaryRepository,
+	recipeRepo domain.RecipeRepository,
+	nutrition domain.NutritionService,
+) *FoodDiaryService {
+	return &FoodDiaryService{
+		diaryRepo:  diaryRepo,
+		recipeRepo: recipeRepo,
+		nutrition:  nutrition,
+	}
+}
+
+// Log stores a diary entry. Entries that reference a recipe take their name
+// and nutrition from the chosen dish (the first one when DishID is 0)
+// scaled by servings; free-form entries must supply a name and calories.
+func (s *FoodDiaryService) Log(ctx context.Context, userID int64, entry *domain.FoodDiaryEntry) (*domain.FoodDiaryEntry, error) {
+	now := time.Now()
+	if entry.EatenAt == 0 {
+		entry.EatenAt = now.Unix()
+	}
+	if entry.EatenAt > now.Add(24*time.Hour).Unix() {
+		return nil, fmt.Errorf("%w: eaten_at cannot be in the future", domain.ErrInvalidInput)
+	}
+
+	switch entry.Meal = strings.ToLower(strings.TrimSpace(entry.Meal)); entry.Meal {
+	case domain.MealBreakfast, domain.MealLunch, domain.MealDinner, domain.MealSnack:
+	default:
+		return nil, fmt.Errorf("%w: meal must be breakfast, lunch, dinner or snack", domain.ErrInvalidInput)
+	}
+
+	if entry.Servings == 0 {
+		entry.Servings = 1
+	}
+	if entry.Servings < 0 || entry.Servings > maxEntryServings {
+		return nil, fmt.Errorf("%w: servings must be greater than 0 and at most %d", domain.ErrInvalidInput, maxEntryServings)
+	}
+
+	entry.Name = strings.TrimSpace(entry.Name)
+	if entry.RecipeID != 0 {
+		if err := s.applyDish(ctx, userID, entry); err != nil {
+			return nil, err
+		}
+	} else if err := validateFreeFormEntry(entry); err != nil {
+		return nil, err
+	}
+
+	entry.UserID = userID
+	if err := s.diaryRepo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to save food diary entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// Day returns a calendar day in loc with its entries, totals and the
+// comparison against the user's targets. An empty date means today.
+func (s *FoodDiaryService) Day(ctx context.Context, userID int64, date string, loc *time.Location) (*domain.DiaryDay, error) {
+	start, err := parseDiaryDate(date, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.diaryRepo.GetRange(ctx, userID, start.Unix(), start.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := optionalTargets(ctx, s.nutrition, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	day := newDiaryDay(start, targets)
+	day.Entries = entries
+	for _, e := range entries {
+		addEntry(&day.Totals, e)
+	}
+	compareWithTargets(day)
+
+	return day, nil
+}
+
+// Summary returns per-day totals between two dates, inclusive. It defaults
+// to the last 7 days and spans at most 31.
+func (s *FoodDiaryService) Summary(ctx context.Context, userID int64, from, to string, loc *time.Location) ([]*domain.DiaryDay, error) {
+	end, err := parseDiaryDate(to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	start := end.AddDate(0, 0, 1-defaultSummaryDays)
+	if from != "" {
+		if start, err = parseDiaryDate(from, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	if start.After(end) {
+		return nil, fmt.Errorf("%w: from must not be after to", domain.ErrInvalidInput)
+	}
+	if !start.AddDate(0, 0, maxSummaryDays).After(end) {
+		return nil, fmt.Errorf("%w: range must not exceed %d days", domain.ErrInvalidInput, maxSummaryDays)
+	}
+
+	entries, err := s.diaryRepo.GetRange(ctx, userID, start.Unix(), end.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := optionalTargets(ctx, s.nutrition, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []*domain.DiaryDay
+	byDate := make(map[string]*domain.DiaryDay)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		day := newDiaryDay(d, targets)
+		days = append(days, day)
+		byDate[day.Date] = day
+	}
+
+	for _, e := range entries {
+		if day, ok := byDate[time.Unix(e.EatenAt, 0).In(loc).Format(diaryDateLayout)]; ok {
+			addEntry(&day.Totals, e)
+		}
+	}
+
+	for _, day := range days {
+		compareWithTargets(day)
+	}
+
+	return days, nil
+}
+
+func (s *FoodDiaryService) Delete(ctx context.Context, userID, id int64) error {
+	return s.diaryRepo.Delete(ctx, id, userID)
+}
+
+func (s *FoodDiaryService) applyDish(ctx context.Context, userID int64, entry *domain.FoodDiaryEntry) error {
+	recipe, err := s.recipeRepo.GetByID(ctx, entry.RecipeID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: unknown recipe_id %d", domain.ErrInvalidInput, entry.RecipeID)
+		}
+		return err
+	}
+
+	if len(recipe.Dishes) == 0 {
+		return fmt.Errorf("%w: recipe %d has no structured dishes", domain.ErrInvalidInput, recipe.ID)
+	}
+
+	dish := &recipe.Dishes[0]
+	if entry.DishID != 0 {
+		dish = nil
+		for i := range recipe.Dishes {
+			if recipe.Dishes[i].ID == entry.DishID {
+				dish = &recipe.Dishes[i]
+				break
+			}
+		}
+		if dish == nil {
+			return fmt.Errorf("%w: dish %d is not part of recipe %d", domain.ErrInvalidInput, entry.DishID, recipe.ID)
+		}
+	}
+
+	entry.DishID = dish.ID
+	if entry.Name == "" {
+		entry.Name = dish.Name
+	}
+	entry.Calories = int(math.Round(float64(dish.Calories) * entry.Servings))
+	entry.ProteinGrams = roundGrams(dish.ProteinGrams * entry.Servings)
+	entry.CarbsGrams = roundGrams(dish.CarbsGrams * entry.Servings)
+	entry.FatGrams = roundGrams(dish.FatGrams * entry.Servings)
+
+	return nil
+}
+
+func validateFreeFormEntry(entry *domain.FoodDiaryEntry) error {
+	if entry.DishID != 0 {
+		return fmt.Errorf("%w: dish_id requires recipe_id", domain.ErrInvalidInput)
+	}
+
+	if entry.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidInput)
+	}
+
+	if entry.Calories <= 0 || entry.Calories > maxEntryCalories {
+		return fmt.Errorf("%w: calories must be between 1 and %d", domain.ErrInvalidInput, maxEntryCalories)
+	}
+
+	for _, g := range []float64{entry.ProteinGrams, entry.CarbsGrams, entry.FatGrams} {
+		if g < 0 || g > maxEntryMacroGrams {
+			return fmt.Errorf("%w: macros must be between 0 and %d grams", domain.ErrInvalidInput, maxEntryMacroGrams)
+		}
+	}
+
+	return nil
+}
+
+// parseDiaryDate returns midnight of a YYYY-MM-DD date in loc, or of today
+// when date is empty.
+func parseDiaryDate(date string, loc *time.Location) (time.Time, error) {
+	if date == "" {
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc), nil
+	}
+
+	t, err := time.ParseInLocation(diaryDateLayout, date, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: dates must use YYYY-MM-DD", domain.ErrInvalidInput)
+	}
+	return t, nil
+}
+
+func newDiaryDay(start time.Time, targets *domain.NutritionTargets) *domain.DiaryDay {
+	return &domain.DiaryDay{
+		Date:    start.Format(diaryDateLayout),
+		Targets: targets,
+	}
+}
+
+func addEntry(totals *domain.MacroTotals, e *domain.FoodDiaryEntry) {
+	totals.Calories += e.Calories
+	totals.ProteinGrams = roundGrams(totals.ProteinGrams + e.ProteinGrams)
+	totals.CarbsGrams = roundGrams(totals.CarbsGrams + e.CarbsGrams)
+	totals.FatGrams = roundGrams(totals.FatGrams + e.FatGrams)
+}
+
+func compareWithTargets(day *domain.DiaryDay) {
+	t := day.Targets
+	if t == nil {
+		return
+	}
+
+	day.Remaining = &domain.MacroTotals{
+		Calories:     t.Calories - day.Totals.Calories,
+		ProteinGrams: roundGrams(float64(t.ProteinGrams) - day.Totals.ProteinGrams),
+		CarbsGrams:   roundGrams(float64(t.CarbsGrams) - day.Totals.CarbsGrams),
+		FatGrams:     roundGrams(float64(t.FatGrams) - day.Totals.FatGrams),
+	}
+}
+
+func roundGrams(g float64) float64 {
+	return math.Round(g*10) / 10
+}
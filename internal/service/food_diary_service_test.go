@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gymapp/internal/domain"
+)
+
+type fakeDiaryRepo struct {
+	entries []*domain.FoodDiaryEntry
+}
+
+func (r *fakeDiaryRepo) Create(ctx context.Context, entry *domain.FoodDiaryEntry) error { return nil }
+
+func (r *fakeDiaryRepo) GetRange(ctx context.Context, userID int64, from, to int64) ([]*domain.FoodDiaryEntry, error) {
+	var out []*domain.FoodDiaryEntry
+	for _, e := range r.entries {
+		if e.EatenAt >= from && e.EatenAt < to {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeDiaryRepo) Delete(ctx context.Context, id, userID int64) error { return nil }
+
+func TestDiarySummaryGroupsByLocalDay(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*3600)
+	at := func(day, hour int) int64 {
+		return time.Date(2025, 3, day, hour, 0, 0, 0, loc).Unix()
+	}
+
+	diary := &fakeDiaryRepo{entries: []*domain.FoodDiaryEntry{
+		{EatenAt: at(1, 1), Calories: 500, ProteinGrams: 30},
+		{EatenAt: at(1, 23), Calories: 700, ProteinGrams: 40.5},
+		{EatenAt: at(3, 12), Calories: 300},
+	}}
+	users := &fakeUserRepo{users: map[int64]*domain.User{
+		1: {ID: 1, Height: 180, Weight: 80, Sex: domain.SexMale, BirthYear: 1995, Goal: domain.GoalMaintain},
+	}}
+	svc := NewFoodDiaryService(diary, nil, NewNutritionService(users, &fakeMeasurementRepo{}))
+
+	days, err := svc.Summary(context.Background(), 1, "2025-03-01", "2025-03-03", loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(days) != 3 || days[0].Date != "2025-03-01" || days[2].Date != "2025-03-03" {
+		t.Fatalf("unexpected days: %+v", days)
+	}
+
+	if days[0].Totals.Calories != 1200 || days[0].Totals.ProteinGrams != 70.5 {
+		t.Errorf("day 1 totals wrong: %+v", days[0].Totals)
+	}
+	if days[1].Totals.Calories != 0 || days[2].Totals.Calories != 300 {
+		t.Errorf("unexpected totals: %+v, %+v", days[1].Totals, days[2].Totals)
+	}
+
+	if days[0].Remaining == nil || days[0].Remaining.Calories != days[0].Targets.Calories-1200 {
+		t.Errorf("remaining not computed: %+v", days[0].Remaining)
+	}
+}
+
+func TestDiarySummaryRejectsLongRanges(t *testing.T) {
+	users := &fakeUserRepo{users: map[int64]*domain.User{1: {ID: 1}}}
+	svc := NewFoodDiaryService(&fakeDiaryRepo{}, nil, NewNutritionService(users, &fakeMeasurementRepo{}))
+	ctx := context.Background()
+
+	for _, r := range [][2]string{
+		{"2025-01-01", "2025-03-01"},
+		{"2025-03-01", "2025-04-01"},
+		{"2025-03-02", "2025-03-01"},
+	} {
+		if _, err := svc.Summary(ctx, 1, r[0], r[1], time.UTC); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input for %s to %s, got %v", r[0], r[1], err)
+		}
+	}
+
+	days, err := svc.Summary(ctx, 1, "2025-03-01", "2025-03-31", time.UTC)
+	if err != nil || len(days) != maxSummaryDays {
+		t.Errorf("expected %d days, got %d (%v)", maxSummaryDays, len(days), err)
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+	"gymapp/pkg/utils"
+)
+
+const (
+	jobRetryBaseDelay = 10 * time.Second
+	jobRetryMaxDelay  = 5 * time.Minute
+)
+
+// JobHandler runs one job of a kind and returns the ID of what it created.
+// Returning an error wrapping domain.ErrInvalidInput, domain.ErrNotFound or
+// domain.ErrQuotaExceeded fails the job without retrying it.
+type JobHandler func(ctx context.Context, userID int64, payload json.RawMessage) (int64, error)
+
+// JobService queues AI generations and runs them on a pool of workers.
+// Jobs live in Postgres, so a job claimed by a worker that dies is picked
+// up again once its lease expires, by this or any other replica.
+type JobService struct {
+	jobRepo  domain.JobRepository
+	cfg      *config.JobsConfig
+	logger   *utils.Logger
+	handlers map[string]JobHandler
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewJobService(jobRepo domain.JobRepository, cfg *config.JobsConfig, logger *utils.Logger) *JobService {
+	return &JobService{
+		jobRepo:  jobRepo,
+		cfg:      cfg,
+		logger:   logger,
+		handlers: make(map[string]JobHandler),
+	}
+}
+
+// Register sets the handler for a job kind. It must be called before Start.
+func (s *JobService) Register(kind string, handler JobHandler) {
+	s.handlers[kind] = handler
+}
+
+func (s *JobService) Enqueue(ctx context.Context, userID int64, kind string, payload any) (*domain.Job, error) {
+	if _, ok := s.handlers[kind]; !ok {
+		return nil, fmt.Errorf("unknown job kind %q", kind)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	job := &domain.Job{
+		UserID:  userID,
+		Kind:    kind,
+		Payload: raw,
+	}
+
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (s *JobService) Get(ctx context.Context, userID, id int64) (*domain.Job, error) {
+	return s.jobRepo.GetByID(ctx, id, userID)
+}
+
+// PurgeFinished deletes jobs that finished longer ago than the retention
+// period.
+func (s *JobService) PurgeFinished(ctx context.Context) error {
+	return s.jobRepo.DeleteFinishedBefore(ctx, time.Now().Add(-s.cfg.Retention).Unix())
+}
+
+// Start launches the workers. They run until Stop is called or ctx is
+// cancelled.
+func (s *JobService) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.work(ctx)
+	}
+}
+
+// Stop cancels the workers and waits for them to hand back in-flight jobs.
+func (s *JobService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *JobService) work(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		ran := s.runNext(ctx)
+		if ran {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.PollInterval):
+		}
+	}
+}
+
+// runNext claims and runs one job. It reports whether there was one, so
+// workers drain the queue without sleeping between jobs.
+func (s *JobService) runNext(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	job, err := s.jobRepo.Claim(ctx, s.cfg.Lease)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) && ctx.Err() == nil {
+			s.logger.Errorf("jobs: %v", err)
+		}
+		return false
+	}
+
+	s.run(ctx, job)
+	return true
+}
+
+func (s *JobService) run(ctx context.Context, job *domain.Job) {
+	// The outcome is recorded even when the worker is shutting down.
+	store := context.WithoutCancel(ctx)
+
+	handler, ok := s.handlers[job.Kind]
+	if !ok {
+		s.finish(store, job, fmt.Errorf("unknown job kind %q", job.Kind), false)
+		return
+	}
+
+	if job.Attempts > s.cfg.MaxAttempts {
+		s.finish(store, job, fmt.Errorf("job exceeded %d attempts", s.cfg.MaxAttempts), false)
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, jobRunTimeout(s.cfg.Lease))
+	defer cancel()
+
+	start := time.Now()
+	resultID, err := handler(runCtx, job.UserID, job.Payload)
+	if err == nil {
+		if err := s.jobRepo.Complete(store, job.ID, job.Attempts, resultID); err != nil {
+			s.logStoreError(job, err)
+			return
+		}
+		s.logger.Infof("jobs: %s job %d completed in %s", job.Kind, job.ID, time.Since(start))
+		return
+	}
+
+	if ctx.Err() != nil {
+		if err := s.jobRepo.Release(store, job.ID, job.Attempts); err != nil {
+			s.logStoreError(job, err)
+		}
+		return
+	}
+
+	permanent := errors.Is(err, domain.ErrInvalidInput) || errors.Is(err, domain.ErrNotFound) ||
+		errors.Is(err, domain.ErrQuotaExceeded)
+	s.finish(store, job, err, !permanent && job.Attempts < s.cfg.MaxAttempts)
+}
+
+// finish records a failed attempt, queueing the job again after a backoff
+// when retry is set.
+func (s *JobService) finish(ctx context.Context, job *domain.Job, jobErr error, retry bool) {
+	var err error
+	if retry {
+		s.logger.Infof("jobs: %s job %d attempt %d failed, retrying: %v", job.Kind, job.ID, job.Attempts, jobErr)
+		err = s.jobRepo.Retry(ctx, job.ID, job.Attempts, jobErrorMessage(jobErr),
+			time.Now().Add(jobRetryDelay(job.Attempts)).Unix())
+	} else {
+		s.logger.Errorf("jobs: %s job %d failed: %v", job.Kind, job.ID, jobErr)
+		err = s.jobRepo.Fail(ctx, job.ID, job.Attempts, jobErrorMessage(jobErr))
+	}
+
+	if err != nil {
+		s.logStoreError(job, err)
+	}
+}
+
+func (s *JobService) logStoreError(job *domain.Job, err error) {
+	if errors.Is(err, domain.ErrJobLost) {
+		s.logger.Infof("jobs: %s job %d attempt %d outlived its lease, another worker owns it now", job.Kind, job.ID, job.Attempts)
+		return
+	}
+	s.logger.Errorf("jobs: job %d: %v", job.ID, err)
+}
+
+// jobRunTimeout is how long a handler may run: a tenth less than the lease,
+// so the outcome is recorded before another worker can claim the job.
+func jobRunTimeout(lease time.Duration) time.Duration {
+	return lease - lease/10
+}
+
+// jobErrorMessage is the error shown on the job. Validation, not-found and
+// quota messages are meant for the user; anything else may carry provider replies
+// or database details and only goes to the log.
+func jobErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput), errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrQuotaExceeded):
+		return err.Error()
+	case errors.Is(err, domain.ErrAIUnavailable):
+		return "AI provider is temporarily unavailable"
+	default:
+		return "generation failed"
+	}
+}
+
+// jobRetryDelay doubles the wait after each failed attempt, up to a cap.
+func jobRetryDelay(attempts int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempts && delay < jobRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, jobRetryMaxDelay)
+}
+
+// decodeJobPayload unmarshals a job payload, failing the job permanently
+// when it does not decode.
+func decodeJobPayload(payload json.RawMessage, v any) error {
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w: malformed job payload: %v", domain.ErrInvalidInput, err)
+	}
+	return nil
+}
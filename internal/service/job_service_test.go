@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+	"gymapp/pkg/utils"
+)
+
+type fakeJobRepo struct {
+	domain.JobRepository
+	completed map[int64]int64
+	retried   map[int64]int64
+	failed    map[int64]string
+	released  []int64
+}
+
+func newFakeJobRepo() *fakeJobRepo {
+	return &fakeJobRepo{
+		completed: make(map[int64]int64),
+		retried:   make(map[int64]int64),
+		failed:    make(map[int64]string),
+	}
+}
+
+func (r *fakeJobRepo) Complete(ctx context.Context, id int64, attempt int, resultID int64) error {
+	r.completed[id] = resultID
+	return nil
+}
+
+func (r *fakeJobRepo) Retry(ctx context.Context, id int64, attempt int, errMsg string, runAfter int64) error {
+	r.retried[id] = runAfter
+	return nil
+}
+
+func (r *fakeJobRepo) Fail(ctx context.Context, id int64, attempt int, errMsg string) error {
+	r.failed[id] = errMsg
+	return nil
+}
+
+func (r *fakeJobRepo) Release(ctx context.Context, id int64, attempt int) error {
+	r.released = append(r.released, id)
+	return nil
+}
+
+func TestJobServiceRecordsOutcomes(t *testing.T) {
+	repo := newFakeJobRepo()
+	svc := NewJobService(repo, &config.JobsConfig{Lease: time.Minute, MaxAttempts: 3},
+		&utils.Logger{Logger: log.New(io.Discard, "", 0)})
+
+	svc.Register("test", func(ctx context.Context, userID int64, payload json.RawMessage) (int64, error) {
+		switch string(payload) {
+		case `"ok"`:
+			return 42, nil
+		case `"invalid"`:
+			return 0, fmt.Errorf("%w: bad request", domain.ErrInvalidInput)
+		default:
+			return 0, errors.New("provider unavailable")
+		}
+	})
+
+	ctx := context.Background()
+	svc.run(ctx, &domain.Job{ID: 1, Kind: "test", Payload: json.RawMessage(`"ok"`), Attempts: 1})
+	svc.run(ctx, &domain.Job{ID: 2, Kind: "test", Payload: json.RawMessage(`"invalid"`), Attempts: 1})
+	svc.run(ctx, &domain.Job{ID: 3, Kind: "test", Payload: json.RawMessage(`"down"`), Attempts: 1})
+	svc.run(ctx, &domain.Job{ID: 4, Kind: "test", Payload: json.RawMessage(`"down"`), Attempts: 3})
+	svc.run(ctx, &domain.Job{ID: 5, Kind: "test", Payload: json.RawMessage(`"ok"`), Attempts: 4})
+
+	if repo.completed[1] != 42 {
+		t.Errorf("job 1: expected result 42, got %v", repo.completed)
+	}
+	if _, ok := repo.failed[2]; !ok {
+		t.Error("job 2: invalid input should fail without retrying")
+	}
+	if _, ok := repo.retried[3]; !ok {
+		t.Error("job 3: transient error should be retried")
+	}
+	if _, ok := repo.failed[4]; !ok {
+		t.Error("job 4: last attempt should fail the job")
+	}
+	if _, ok := repo.failed[5]; !ok {
+		t.Error("job 5: job past max attempts should fail without running")
+	}
+}
+
+func TestJobServiceReleasesOnShutdown(t *testing.T) {
+	repo := newFakeJobRepo()
+	svc := NewJobService(repo, &config.JobsConfig{Lease: time.Minute, MaxAttempts: 3},
+		&utils.Logger{Logger: log.New(io.Discard, "", 0)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	svc.Register("test", func(ctx context.Context, userID int64, payload json.RawMessage) (int64, error) {
+		cancel()
+		return 0, ctx.Err()
+	})
+
+	svc.run(ctx, &domain.Job{ID: 7, Kind: "test", Attempts: 1})
+
+	if len(repo.released) != 1 || repo.released[0] != 7 {
+		t.Errorf("expected job 7 to be released, got %v", repo.released)
+	}
+	if len(repo.retried)+len(repo.failed) != 0 {
+		t.Error("interrupted job should not count as a failed attempt")
+	}
+}
+
+func TestJobHandlerDeadlineBeforeLease(t *testing.T) {
+	repo := newFakeJobRepo()
+	svc := NewJobService(repo, &config.JobsConfig{Lease: time.Minute, MaxAttempts: 3},
+		&utils.Logger{Logger: log.New(io.Discard, "", 0)})
+
+	var remaining time.Duration
+	svc.Register("test", func(ctx context.Context, userID int64, payload json.RawMessage) (int64, error) {
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return 1, nil
+	})
+
+	svc.run(context.Background(), &domain.Job{ID: 1, Kind: "test", Attempts: 1})
+
+	if remaining <= 0 || remaining > 54*time.Second {
+		t.Errorf("expected the handler deadline to leave a margin before the lease, got %s", remaining)
+	}
+}
+
+func TestJobRetryDelay(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		3:  40 * time.Second,
+		10: jobRetryMaxDelay,
+	} {
+		if got := jobRetryDelay(attempts); got != want {
+			t.Errorf("jobRetryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"gymapp/internal/domain"
+)
+
+type mealShare struct {
+	meal  string
+	share float64
+}
+
+// mealShares splits the daily calorie target across the meals of a day, in
+// the order they are eaten.
+var mealShares = []mealShare{
+	{domain.MealBreakfast, 0.25},
+	{domain.MealLunch, 0.30},
+	{domain.MealDinner, 0.30},
+	{domain.MealSnack, 0.15},
+}
+
+type mealPlanReply struct {
+	Meals []mealReply `json:"meals"`
+}
+
+type mealReply struct {
+	Day  string `json:"day"`
+	Meal string `json:"meal"`
+	domain.Dish
+}
+
+// mealSlotKey identifies a slot within a plan.
+func mealSlotKey(day, meal string) string {
+	return day + "/" + meal
+}
+
+// mealIndex is the position of meal within a day, or -1 if it is unknown.
+func mealIndex(meal string) int {
+	return slices.IndexFunc(mealShares, func(m mealShare) bool { return m.meal == meal })
+}
+
+// mealCalories is the share of the daily target a meal is sized to.
+func mealCalories(calorieTarget int, meal string) int {
+	if i := mealIndex(meal); i >= 0 {
+		return int(math.Round(float64(calorieTarget) * mealShares[i].share))
+	}
+	return 0
+}
+
+// weekSlotSpecs lists every slot of a week in day then meal order.
+func weekSlotSpecs(calorieTarget int) []domain.MealSlotSpec {
+	specs := make([]domain.MealSlotSpec, 0, len(weekdays)*len(mealShares))
+	for _, day := range weekdays {
+		for _, m := range mealShares {
+			specs = append(specs, domain.MealSlotSpec{
+				Day:      day,
+				Meal:     m.meal,
+				Calories: mealCalories(calorieTarget, m.meal),
+			})
+		}
+	}
+	return specs
+}
+
+// parseMealSlots decodes model output into slots, keeping only the first
+// usable dish for each requested slot in the order of specs. Dishes are
+// repaired like recipe suggestions; it fails if no requested slot is
+// filled.
+func parseMealSlots(raw string, specs []domain.MealSlotSpec) ([]domain.MealSlot, error) {
+	var reply mealPlanReply
+	if err := json.Unmarshal([]byte(extractJSONObject(raw)), &reply); err != nil {
+		return nil, fmt.Errorf("meal plan is not valid JSON: %w", err)
+	}
+
+	dishes := make(map[string]domain.Dish)
+	for _, m := range reply.Meals {
+		key := mealSlotKey(strings.ToLower(strings.TrimSpace(m.Day)), strings.ToLower(strings.TrimSpace(m.Meal)))
+		if _, ok := dishes[key]; ok {
+			continue
+		}
+		dish := m.Dish
+		if repairDish(&dish) {
+			dishes[key] = dish
+		}
+	}
+
+	var slots []domain.MealSlot
+	for _, spec := range specs {
+		if dish, ok := dishes[mealSlotKey(spec.Day, spec.Meal)]; ok {
+			slots = append(slots, domain.MealSlot{Day: spec.Day, Meal: spec.Meal, Dish: dish})
+		}
+	}
+
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("no valid meals in response")
+	}
+
+	return slots, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"gymapp/internal/domain"
+)
+
+func TestParseMealSlotsKeepsRequestedSlots(t *testing.T) {
+	specs := []domain.MealSlotSpec{
+		{Day: "monday", Meal: domain.MealBreakfast, Calories: 500},
+		{Day: "monday", Meal: domain.MealLunch, Calories: 600},
+	}
+	raw := `{"meals": [
+		{"day": "Monday", "meal": "Lunch", "name": "Bowl", "ingredients": [{"name": "rice", "quantity": 80, "unit": "g"}]},
+		{"day": "monday", "meal": "lunch", "name": "Duplicate", "ingredients": [{"name": "rice", "quantity": 80, "unit": "g"}]},
+		{"day": "tuesday", "meal": "dinner", "name": "Unrequested", "ingredients": [{"name": "fish", "quantity": 1, "unit": "pcs"}]},
+		{"day": "monday", "meal": "breakfast", "name": "", "ingredients": []}
+	]}`
+
+	slots, err := parseMealSlots(raw, specs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(slots) != 1 || slots[0].Day != "monday" || slots[0].Meal != domain.MealLunch || slots[0].Dish.Name != "Bowl" {
+		t.Errorf("unexpected slots: %+v", slots)
+	}
+
+	if _, err := parseMealSlots(`{"meals": []}`, specs); err == nil {
+		t.Error("expected error for empty meal plan")
+	}
+}
+
+func TestMockMealPlanFillsWeek(t *testing.T) {
+	specs := weekSlotSpecs(2000)
+	raw, err := NewMockAIProvider().GenerateMealPlan(context.Background(), &domain.MealPlanInput{
+		CalorieTarget: 2000,
+		Slots:         specs,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	slots, err := parseMealSlots(raw, specs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(slots) != 28 {
+		t.Fatalf("expected 28 slots, got %d", len(slots))
+	}
+
+	day := 0
+	for _, slot := range slots[:4] {
+		day += slot.Dish.Calories
+	}
+	if day != 2000 {
+		t.Errorf("expected monday to total 2000 kcal, got %d", day)
+	}
+}
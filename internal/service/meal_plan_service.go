@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gymapp/internal/domain"
+)
+
+const (
+	minMealPlanCalories   = 1000
+	maxMealPlanCalories   = 6000
+	maxMealPlanPreference = 500
+)
+
+type MealPlanService struct {
+	mealPlanRepo domain.MealPlanRepository
+	userRepo     domain.UserRepository
+	nutrition    domain.NutritionService
+	aiProvider   domain.AIProvider
+	usage        domain.UsageService
+}
+
+func NewMealPlanService(
+	mealPlanRepo domain.MealPlanRepository,
+	userRepo domain.UserRepository,
+	nutrition domain.NutritionService,
+	aiProvider domain.AIProvider,
+	usage domain.UsageService,
+) *MealPlanService {
+	return &MealPlanService{
+		mealPlanRepo: mealPlanRepo,
+		userRepo:     userRepo,
+		nutrition:    nutrition,
+		aiProvider:   aiProvider,
+		usage:        usage,
+	}
+}
+
+// ValidateRequest normalizes and checks a request, and the user's AI quota,
+// before it is queued.
+func (s *MealPlanService) ValidateRequest(ctx context.Context, userID int64, req *domain.GenerateMealPlanRequest) error {
+	req.Preferences = strings.TrimSpace(req.Preferences)
+	if len(req.Preferences) > maxMealPlanPreference {
+		return fmt.Errorf("%w: preferences must be at most %d characters", domain.ErrInvalidInput, maxMealPlanPreference)
+	}
+
+	if req.Calories != 0 && (req.Calories < minMealPlanCalories || req.Calories > maxMealPlanCalories) {
+		return fmt.Errorf("%w: calories must be between %d and %d", domain.ErrInvalidInput, minMealPlanCalories, maxMealPlanCalories)
+	}
+
+	return s.usage.CheckQuota(ctx, userID)
+}
+
+// Generate plans a week of meals sized to the requested calories or, when
+// none are given, to the user's nutrition targets. Slots the model leaves
+// out, gets wrong or fills with an allergen are asked for once more; the
+// plan is only saved once every slot is filled.
+func (s *MealPlanService) Generate(ctx context.Context, userID int64, req *domain.GenerateMealPlanRequest) (*domain.MealPlan, error) {
+	return s.StreamPlan(ctx, userID, req, nil)
+}
+
+// StreamPlan generates a plan like Generate while passing the model's reply
+// to stream as it is written. The plan is saved once the reply is complete.
+func (s *MealPlanService) StreamPlan(ctx context.Context, userID int64, req *domain.GenerateMealPlanRequest, stream domain.StreamFunc) (*domain.MealPlan, error) {
+	if err := s.ValidateRequest(ctx, userID, req); err != nil {
+		return nil, err
+	}
+	preferences := req.Preferences
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	targets, err := optionalTargets(ctx, s.nutrition, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	calories := req.Calories
+	if calories == 0 {
+		if targets == nil {
+			return nil, fmt.Errorf("%w: set calories or complete the profile via /users/me", domain.ErrInvalidInput)
+		}
+		calories = targets.Calories
+	}
+
+	input := &domain.MealPlanInput{
+		Goal:          user.Goal,
+		Preferences:   preferences,
+		CalorieTarget: calories,
+		Targets:       targets,
+		Diet:          user.Diet,
+		Slots:         weekSlotSpecs(calories),
+		Stream:        stream,
+		SkipCache:     req.NoCache,
+		Usage:         &domain.TokenUsage{},
+	}
+
+	slots, err := s.generateSlots(ctx, userID, domain.UsageMealPlan, user.Diet, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate meal plan: %w", err)
+	}
+
+	if missing := missingMealSlots(input.Slots, slots); len(missing) > 0 {
+		retry := *input
+		retry.Slots = missing
+		retry.Avoid = mealDishNames(slots)
+		retry.Stream = nil
+		retry.SkipCache = true
+		retry.Usage = &domain.TokenUsage{}
+
+		if more, err := s.generateSlots(ctx, userID, domain.UsageMealPlan, user.Diet, &retry); err == nil {
+			slots = append(slots, more...)
+		} else if errors.Is(err, domain.ErrAIUnavailable) {
+			return nil, fmt.Errorf("failed to generate meal plan: %w", err)
+		}
+
+		if missing = missingMealSlots(input.Slots, slots); len(missing) > 0 {
+			return nil, fmt.Errorf("failed to generate meal plan: %d of %d meals are missing", len(missing), len(input.Slots))
+		}
+		sortMealSlots(slots)
+	}
+
+	plan := &domain.MealPlan{
+		UserID:        userID,
+		CalorieTarget: calories,
+		Preferences:   preferences,
+		Slots:         slots,
+	}
+
+	if err := s.mealPlanRepo.Create(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to save meal plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// RunJob is the JobHandler for domain.JobKindMealPlan.
+func (s *MealPlanService) RunJob(ctx context.Context, userID int64, payload json.RawMessage) (int64, error) {
+	var req domain.GenerateMealPlanRequest
+	if err := decodeJobPayload(payload, &req); err != nil {
+		return 0, err
+	}
+
+	plan, err := s.Generate(ctx, userID, &req)
+	if err != nil {
+		return 0, err
+	}
+
+	return plan.ID, nil
+}
+
+func (s *MealPlanService) List(ctx context.Context, userID int64, limit, offset int) ([]*domain.MealPlan, error) {
+	plans, err := s.mealPlanRepo.GetByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plan := range plans {
+		sortMealSlots(plan.Slots)
+	}
+
+	return plans, nil
+}
+
+func (s *MealPlanService) Get(ctx context.Context, userID, id int64) (*domain.MealPlan, error) {
+	plan, err := s.mealPlanRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sortMealSlots(plan.Slots)
+	return plan, nil
+}
+
+func (s *MealPlanService) Delete(ctx context.Context, userID, id int64) error {
+	return s.mealPlanRepo.Delete(ctx, id, userID)
+}
+
+// RegenerateSlot replaces one meal of the plan, asking for a dish that is
+// not already in it, and returns the updated plan.
+func (s *MealPlanService) RegenerateSlot(ctx context.Context, userID, id int64, day, meal string) (*domain.MealPlan, error) {
+	day = strings.ToLower(strings.TrimSpace(day))
+	if !slices.Contains(weekdays, day) {
+		return nil, fmt.Errorf("%w: day must be a weekday name such as monday", domain.ErrInvalidInput)
+	}
+
+	meal = strings.ToLower(strings.TrimSpace(meal))
+	if mealIndex(meal) < 0 {
+		return nil, fmt.Errorf("%w: meal must be breakfast, lunch, dinner or snack", domain.ErrInvalidInput)
+	}
+
+	plan, err := s.mealPlanRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.usage.CheckQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	targets, err := optionalTargets(ctx, s.nutrition, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &domain.MealPlanInput{
+		Goal:          user.Goal,
+		Preferences:   plan.Preferences,
+		CalorieTarget: plan.CalorieTarget,
+		Targets:       targets,
+		Diet:          user.Diet,
+		Slots: []domain.MealSlotSpec{
+			{Day: day, Meal: meal, Calories: mealCalories(plan.CalorieTarget, meal)},
+		},
+		Avoid: mealDishNames(plan.Slots),
+		// Asking for a replacement should never return the cached dish.
+		SkipCache: true,
+		Usage:     &domain.TokenUsage{},
+	}
+
+	slots, err := s.generateSlots(ctx, userID, domain.UsageMealPlanSlot, user.Diet, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to regenerate meal: %w", err)
+	}
+
+	slot := slots[0]
+	if err := s.mealPlanRepo.UpsertSlot(ctx, plan.ID, &slot); err != nil {
+		return nil, fmt.Errorf("failed to save meal: %w", err)
+	}
+
+	replaced := false
+	for i := range plan.Slots {
+		if plan.Slots[i].Day == day && plan.Slots[i].Meal == meal {
+			plan.Slots[i] = slot
+			replaced = true
+		}
+	}
+	if !replaced {
+		plan.Slots = append(plan.Slots, slot)
+	}
+
+	sortMealSlots(plan.Slots)
+	return plan, nil
+}
+
+// generateSlots asks the provider for the slots in input and returns those
+// it filled with a usable dish free of the user's allergens.
+func (s *MealPlanService) generateSlots(ctx context.Context, userID int64, endpoint string, diet domain.DietaryPreferences, input *domain.MealPlanInput) (_ []domain.MealSlot, err error) {
+	defer func() { s.usage.RecordAttempt(ctx, userID, endpoint, *input.Usage, err) }()
+
+	input.Validate = func(reply string) error {
+		_, err := replyMealSlots(reply, input.Slots, diet)
+		return err
+	}
+
+	raw, err := s.aiProvider.GenerateMealPlan(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return replyMealSlots(raw, input.Slots, diet)
+}
+
+// replyMealSlots parses a meal plan reply for specs and screens the meals
+// against diet.
+func replyMealSlots(reply string, specs []domain.MealSlotSpec, diet domain.DietaryPreferences) ([]domain.MealSlot, error) {
+	slots, err := parseMealSlots(reply, specs)
+	if err != nil {
+		return nil, err
+	}
+	return screenMealSlots(diet, slots)
+}
+
+// missingMealSlots returns the specs that have no slot in slots.
+func missingMealSlots(specs []domain.MealSlotSpec, slots []domain.MealSlot) []domain.MealSlotSpec {
+	filled := make(map[string]bool, len(slots))
+	for _, slot := range slots {
+		filled[mealSlotKey(slot.Day, slot.Meal)] = true
+	}
+
+	var missing []domain.MealSlotSpec
+	for _, spec := range specs {
+		if !filled[mealSlotKey(spec.Day, spec.Meal)] {
+			missing = append(missing, spec)
+		}
+	}
+	return missing
+}
+
+// mealDishNames lists the distinct dish names in slots.
+func mealDishNames(slots []domain.MealSlot) []string {
+	var names []string
+	for _, slot := range slots {
+		if !slices.Contains(names, slot.Dish.Name) {
+			names = append(names, slot.Dish.Name)
+		}
+	}
+	return names
+}
+
+// screenMealSlots drops meals that contain one of the user's allergens,
+// leaving their slots empty, and flags the rest. It fails when every meal
+// was rejected.
+func screenMealSlots(diet domain.DietaryPreferences, slots []domain.MealSlot) ([]domain.MealSlot, error) {
+	rules := newDietRules(diet)
+
+	var rejected []string
+	kept := make([]domain.MealSlot, 0, len(slots))
+	for _, slot := range slots {
+		if allergens := rules.screen(&slot.Dish); len(allergens) > 0 {
+			rejected = appendUnique(rejected, allergens...)
+			continue
+		}
+		kept = append(kept, slot)
+	}
+
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("every meal contained a declared allergen (%s)", strings.Join(rejected, ", "))
+	}
+
+	return kept, nil
+}
+
+// sortMealSlots orders slots by weekday and then by meal of the day.
+func sortMealSlots(slots []domain.MealSlot) {
+	slices.SortStableFunc(slots, func(a, b domain.MealSlot) int {
+		if d := slices.Index(weekdays, a.Day) - slices.Index(weekdays, b.Day); d != 0 {
+			return d
+		}
+		return mealIndex(a.Meal) - mealIndex(b.Meal)
+	})
+}
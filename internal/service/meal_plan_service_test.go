@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gymapp/internal/domain"
+)
+
+type fakeMealPlanRepo struct {
+	created []*domain.MealPlan
+}
+
+func (r *fakeMealPlanRepo) Create(ctx context.Context, plan *domain.MealPlan) error {
+	plan.ID = int64(len(r.created) + 1)
+	r.created = append(r.created, plan)
+	return nil
+}
+
+func (r *fakeMealPlanRepo) GetByID(ctx context.Context, id, userID int64) (*domain.MealPlan, error) {
+	return nil, nil
+}
+
+func (r *fakeMealPlanRepo) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.MealPlan, error) {
+	return nil, nil
+}
+
+func (r *fakeMealPlanRepo) Delete(ctx context.Context, id, userID int64) error { return nil }
+
+func (r *fakeMealPlanRepo) UpsertSlot(ctx context.Context, planID int64, slot *domain.MealSlot) error {
+	return nil
+}
+
+// partialMealPlanProvider fills at most fill slots of each request.
+type partialMealPlanProvider struct {
+	fakeAIProvider
+	fill   []int
+	inputs []*domain.MealPlanInput
+}
+
+func (p *partialMealPlanProvider) GenerateMealPlan(ctx context.Context, input *domain.MealPlanInput) (string, error) {
+	n := len(input.Slots)
+	if call := len(p.inputs); call < len(p.fill) {
+		n = min(n, p.fill[call])
+	}
+	p.inputs = append(p.inputs, input)
+
+	var reply mealPlanReply
+	for _, spec := range input.Slots[:n] {
+		reply.Meals = append(reply.Meals, mealReply{Day: spec.Day, Meal: spec.Meal, Dish: domain.Dish{
+			Name:        spec.Day + " " + spec.Meal,
+			Calories:    spec.Calories,
+			Ingredients: []domain.DishIngredient{{Name: "oats", Quantity: 50, Unit: "g"}},
+		}})
+	}
+
+	raw, err := json.Marshal(reply)
+	return string(raw), err
+}
+
+func TestGenerateMealPlanFillsEverySlot(t *testing.T) {
+	users := &fakeUserRepo{users: map[int64]*domain.User{1: {ID: 1}}}
+	req := &domain.GenerateMealPlanRequest{Calories: 2000}
+
+	plans := &fakeMealPlanRepo{}
+	ai := &partialMealPlanProvider{fill: []int{20}}
+	svc := NewMealPlanService(plans, users, NewNutritionService(users, &fakeMeasurementRepo{}), ai, newTestUsageService(0, 0))
+
+	plan, err := svc.Generate(context.Background(), 1, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Slots) != 28 || len(plans.created) != 1 {
+		t.Fatalf("expected a saved plan with 28 meals, got %d", len(plan.Slots))
+	}
+	if len(ai.inputs) != 2 || len(ai.inputs[1].Slots) != 8 || len(ai.inputs[1].Avoid) != 20 {
+		t.Errorf("expected the 8 missing meals to be asked for again, got %d calls", len(ai.inputs))
+	}
+	if plan.Slots[27].Day != "sunday" || plan.Slots[27].Meal != domain.MealSnack {
+		t.Errorf("expected slots in week order, got %s %s last", plan.Slots[27].Day, plan.Slots[27].Meal)
+	}
+
+	plans = &fakeMealPlanRepo{}
+	ai = &partialMealPlanProvider{fill: []int{20, 5}}
+	svc = NewMealPlanService(plans, users, NewNutritionService(users, &fakeMeasurementRepo{}), ai, newTestUsageService(0, 0))
+
+	if _, err := svc.Generate(context.Background(), 1, req); err == nil {
+		t.Fatal("expected an error when meals are still missing")
+	}
+	if len(plans.created) != 0 {
+		t.Error("an incomplete plan should not be saved")
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gymapp/internal/domain"
+)
+
+// activityFactors are the usual TDEE multipliers applied to BMR.
+var activityFactors = map[string]float64{
+	domain.ActivitySedentary:  1.2,
+	domain.ActivityLight:      1.375,
+	domain.ActivityModerate:   1.55,
+	domain.ActivityActive:     1.725,
+	domain.ActivityVeryActive: 1.9,
+}
+
+// goalTargets sets the calorie adjustment relative to TDEE and the protein
+// intake in grams per kg of body weight for each goal.
+var goalTargets = map[string]struct {
+	calorieFactor float64
+	proteinPerKg  float64
+}{
+	domain.GoalCut:      {calorieFactor: 0.8, proteinPerKg: 2.2},
+	domain.GoalMaintain: {calorieFactor: 1.0, proteinPerKg: 1.8},
+	domain.GoalBulk:     {calorieFactor: 1.1, proteinPerKg: 2.0},
+}
+
+const (
+	fatCalorieShare   = 0.25
+	minFatPerKg       = 0.6
+	minCaloriesMale   = 1500
+	minCaloriesFemale = 1200
+)
+
+type NutritionService struct {
+	userRepo        domain.UserRepository
+	measurementRepo domain.BodyMeasurementRepository
+}
+
+func NewNutritionService(
+	userRepo domain.UserRepository,
+	measurementRepo domain.BodyMeasurementRepository,
+) *NutritionService {
+	return &NutritionService{
+		userRepo:        userRepo,
+		measurementRepo: measurementRepo,
+	}
+}
+
+// Targets computes the user's daily targets, preferring the latest logged
+// weight over the profile value.
+func (s *NutritionService) Targets(ctx context.Context, userID int64) (*domain.NutritionTargets, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to load profile: %w", err)
+	}
+
+	weightKg := float64(user.Weight)
+	latest, err := s.measurementRepo.GetLatestWeight(ctx, userID)
+	switch {
+	case err == nil:
+		weightKg = latest.WeightKg
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, fmt.Errorf("failed to load measurements: %w", err)
+	}
+
+	return computeNutritionTargets(user, weightKg, time.Now())
+}
+
+// optionalTargets returns nil targets instead of an error when the profile is
+// too incomplete to compute them, so generation can proceed without them.
+func optionalTargets(ctx context.Context, nutrition domain.NutritionService, userID int64) (*domain.NutritionTargets, error) {
+	targets, err := nutrition.Targets(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to compute nutrition targets: %w", err)
+	}
+	return targets, nil
+}
+
+// computeNutritionTargets applies Mifflin-St Jeor, scales by activity level
+// (sedentary when unset) and splits the goal calories into protein by body
+// weight, a quarter of calories from fat and the rest from carbs.
+func computeNutritionTargets(user *domain.User, weightKg float64, now time.Time) (*domain.NutritionTargets, error) {
+	if user.Height <= 0 || weightKg <= 0 || user.BirthYear <= 0 ||
+		(user.Sex != domain.SexMale && user.Sex != domain.SexFemale) {
+		return nil, fmt.Errorf("%w: profile incomplete: set height, weight, sex and birth_year via /users/me", domain.ErrInvalidInput)
+	}
+
+	sexOffset, minCalories := -161.0, minCaloriesFemale
+	if user.Sex == domain.SexMale {
+		sexOffset, minCalories = 5, minCaloriesMale
+	}
+
+	activity := user.ActivityLevel
+	if _, ok := activityFactors[activity]; !ok {
+		activity = domain.ActivitySedentary
+	}
+
+	goal := user.Goal
+	if _, ok := goalTargets[goal]; !ok {
+		goal = domain.GoalMaintain
+	}
+
+	age := float64(now.Year() - user.BirthYear)
+	bmr := 10*weightKg + 6.25*float64(user.Height) - 5*age + sexOffset
+	tdee := bmr * activityFactors[activity]
+
+	calories := math.Max(tdee*goalTargets[goal].calorieFactor, float64(minCalories))
+	protein := weightKg * goalTargets[goal].proteinPerKg
+	fat := math.Max(calories*fatCalorieShare/9, weightKg*minFatPerKg)
+	carbs := math.Max((calories-protein*4-fat*9)/4, 0)
+
+	return &domain.NutritionTargets{
+		Goal:          goal,
+		ActivityLevel: activity,
+		WeightKg:      weightKg,
+		BMR:           int(math.Round(bmr)),
+		TDEE:          int(math.Round(tdee)),
+		Calories:      int(math.Round(calories)),
+		ProteinGrams:  int(math.Round(protein)),
+		CarbsGrams:    int(math.Round(carbs)),
+		FatGrams:      int(math.Round(fat)),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gymapp/internal/domain"
+)
+
+func TestComputeNutritionTargets(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	user := &domain.User{
+		Height:        180,
+		Goal:          domain.GoalCut,
+		Sex:           domain.SexMale,
+		BirthYear:     1995,
+		ActivityLevel: domain.ActivityModerate,
+	}
+
+	targets, err := computeNutritionTargets(user, 80, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 10*80 + 6.25*180 - 5*30 + 5 = 1780; * 1.55 = 2759; * 0.8 = 2207.2
+	want := domain.NutritionTargets{
+		Goal:          domain.GoalCut,
+		ActivityLevel: domain.ActivityModerate,
+		WeightKg:      80,
+		BMR:           1780,
+		TDEE:          2759,
+		Calories:      2207,
+		ProteinGrams:  176,
+		CarbsGrams:    238,
+		FatGrams:      61,
+	}
+	if *targets != want {
+		t.Errorf("got %+v, want %+v", *targets, want)
+	}
+}
+
+func TestComputeNutritionTargetsDefaultsAndFloor(t *testing.T) {
+	user := &domain.User{Height: 150, Sex: domain.SexFemale, BirthYear: 1950, Goal: domain.GoalCut}
+
+	targets, err := computeNutritionTargets(user, 45, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if targets.ActivityLevel != domain.ActivitySedentary || targets.Calories != minCaloriesFemale {
+		t.Errorf("expected sedentary default and calorie floor, got %+v", targets)
+	}
+}
+
+func TestComputeNutritionTargetsRequiresProfile(t *testing.T) {
+	user := &domain.User{Height: 180, BirthYear: 1995}
+
+	_, err := computeNutritionTargets(user, 80, time.Now())
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput without sex, got %v", err)
+	}
+}
+
+func TestTargetsForUnknownUser(t *testing.T) {
+	svc := NewNutritionService(&fakeUserRepo{}, &fakeMeasurementRepo{})
+
+	if _, err := svc.Targets(context.Background(), 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing user, got %v", err)
+	}
+}
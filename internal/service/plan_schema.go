@@ -0,0 +1,174 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gymapp/internal/domain"
+)
+
+const (
+	maxPlanWeeks       = 12
+	maxExerciseSets    = 10
+	maxExerciseReps    = 100
+	maxExerciseMinutes = 180
+)
+
+var weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// exerciseCatalog resolves plan exercises against the exercise library by ID
+// or, failing that, by case-insensitive name.
+type exerciseCatalog struct {
+	byID   map[int64]*domain.Exercise
+	byName map[string]*domain.Exercise
+}
+
+func newExerciseCatalog(exercises []*domain.Exercise) *exerciseCatalog {
+	if len(exercises) == 0 {
+		return nil
+	}
+
+	c := &exerciseCatalog{
+		byID:   make(map[int64]*domain.Exercise, len(exercises)),
+		byName: make(map[string]*domain.Exercise, len(exercises)),
+	}
+	for _, ex := range exercises {
+		c.byID[ex.ID] = ex
+		c.byName[strings.ToLower(ex.Name)] = ex
+	}
+
+	return c
+}
+
+func (c *exerciseCatalog) resolve(ex domain.PlanExercise) *domain.Exercise {
+	if entry, ok := c.byID[ex.ExerciseID]; ok {
+		return entry
+	}
+	return c.byName[strings.ToLower(strings.TrimSpace(ex.Name))]
+}
+
+// parseWorkoutPlan decodes model output into a WorkoutPlan and repairs what
+// can be repaired: unknown or duplicate days and exercises without a usable
+// prescription are dropped, numbers are clamped, and intensity defaults to
+// moderate. With a non-empty catalog, exercises that are not in it are
+// dropped and the rest take their catalog ID and name. It fails if nothing
+// trainable is left.
+func parseWorkoutPlan(raw string, availableDays int, catalog []*domain.Exercise) (*domain.WorkoutPlan, error) {
+	lib := newExerciseCatalog(catalog)
+
+	var plan domain.WorkoutPlan
+	if err := json.Unmarshal([]byte(extractJSONObject(raw)), &plan); err != nil {
+		return nil, fmt.Errorf("plan is not valid JSON: %w", err)
+	}
+
+	if len(plan.Weeks) > maxPlanWeeks {
+		plan.Weeks = plan.Weeks[:maxPlanWeeks]
+	}
+
+	weeks := make([]domain.PlanWeek, 0, len(plan.Weeks))
+	for _, week := range plan.Weeks {
+		week.Days = repairDays(week.Days, availableDays, lib)
+		if len(week.Days) == 0 {
+			continue
+		}
+		week.Week = len(weeks) + 1
+		weeks = append(weeks, week)
+	}
+
+	if len(weeks) == 0 {
+		return nil, fmt.Errorf("plan has no valid training days")
+	}
+
+	plan.Weeks = weeks
+	if plan.DurationWeeks < len(weeks) {
+		plan.DurationWeeks = len(weeks)
+	}
+
+	return &plan, nil
+}
+
+func repairDays(days []domain.PlanDay, availableDays int, lib *exerciseCatalog) []domain.PlanDay {
+	seen := make(map[string]bool)
+	out := make([]domain.PlanDay, 0, len(days))
+
+	for _, day := range days {
+		day.Day = strings.ToLower(strings.TrimSpace(day.Day))
+		if !isWeekday(day.Day) || seen[day.Day] {
+			continue
+		}
+
+		day.Exercises = repairExercises(day.Exercises, lib)
+		if len(day.Exercises) == 0 {
+			continue
+		}
+
+		seen[day.Day] = true
+		out = append(out, day)
+		if availableDays > 0 && len(out) == availableDays {
+			break
+		}
+	}
+
+	return out
+}
+
+func repairExercises(exercises []domain.PlanExercise, lib *exerciseCatalog) []domain.PlanExercise {
+	out := make([]domain.PlanExercise, 0, len(exercises))
+
+	for _, ex := range exercises {
+		if lib != nil {
+			entry := lib.resolve(ex)
+			if entry == nil {
+				continue
+			}
+			ex.ExerciseID, ex.Name = entry.ID, entry.Name
+		}
+
+		ex.Name = strings.TrimSpace(ex.Name)
+		if ex.Name == "" {
+			continue
+		}
+
+		ex.Sets = clamp(ex.Sets, 0, maxExerciseSets)
+		ex.Reps = clamp(ex.Reps, 0, maxExerciseReps)
+		ex.DurationMinutes = clamp(ex.DurationMinutes, 0, maxExerciseMinutes)
+
+		hasSets := ex.Sets > 0 && ex.Reps > 0
+		if !hasSets {
+			ex.Sets, ex.Reps = 0, 0
+		}
+		if !hasSets && ex.DurationMinutes == 0 {
+			continue
+		}
+
+		switch ex.Intensity = strings.ToLower(strings.TrimSpace(ex.Intensity)); ex.Intensity {
+		case domain.IntensityLow, domain.IntensityModerate, domain.IntensityHigh:
+		default:
+			ex.Intensity = domain.IntensityModerate
+		}
+
+		out = append(out, ex)
+	}
+
+	return out
+}
+
+func isWeekday(day string) bool {
+	for _, d := range weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"gymapp/internal/domain"
+)
+
+func TestParseWorkoutPlanRepairs(t *testing.T) {
+	raw := "Here is your plan:\n```json\n" + `{
+  "duration_weeks": 0,
+  "weeks": [
+    {"week": 7, "days": [
+      {"day": "Monday", "focus": "legs", "exercises": [
+        {"name": "Squat", "sets": 25, "reps": 5, "intensity": "EXTREME"},
+        {"name": "", "sets": 3, "reps": 10},
+        {"name": "Stretching"}
+      ]},
+      {"day": "monday", "focus": "duplicate", "exercises": [{"name": "Row", "sets": 3, "reps": 8}]},
+      {"day": "someday", "focus": "invalid", "exercises": [{"name": "Row", "sets": 3, "reps": 8}]},
+      {"day": "wednesday", "focus": "cardio", "exercises": [{"name": "Run", "duration_minutes": 30, "intensity": "low"}]},
+      {"day": "friday", "focus": "over budget", "exercises": [{"name": "Row", "sets": 3, "reps": 8}]}
+    ]},
+    {"week": 8, "days": []}
+  ]
+}` + "\n```"
+
+	plan, err := parseWorkoutPlan(raw, 2, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.Weeks) != 1 || plan.Weeks[0].Week != 1 || plan.DurationWeeks != 1 {
+		t.Fatalf("weeks not renumbered: %+v", plan)
+	}
+
+	days := plan.Weeks[0].Days
+	if len(days) != 2 || days[0].Day != "monday" || days[1].Day != "wednesday" {
+		t.Fatalf("unexpected days: %+v", days)
+	}
+
+	squat := days[0].Exercises
+	if len(squat) != 1 {
+		t.Fatalf("expected invalid exercises dropped, got %+v", squat)
+	}
+	if squat[0].Sets != maxExerciseSets || squat[0].Intensity != domain.IntensityModerate {
+		t.Errorf("exercise not repaired: %+v", squat[0])
+	}
+}
+
+func TestParseWorkoutPlanRejectsEmpty(t *testing.T) {
+	for _, raw := range []string{"not json", `{"weeks": []}`} {
+		if _, err := parseWorkoutPlan(raw, 3, nil); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestParseWorkoutPlanUsesCatalog(t *testing.T) {
+	catalog := []*domain.Exercise{
+		{ID: 1, Name: "Barbell Squat"},
+		{ID: 2, Name: "Plank"},
+	}
+	raw := `{"weeks": [{"days": [{"day": "monday", "exercises": [
+		{"exercise_id": 1, "name": "Squats", "sets": 3, "reps": 5},
+		{"name": "plank", "sets": 3, "reps": 1},
+		{"exercise_id": 99, "name": "Made-Up Press", "sets": 3, "reps": 8}
+	]}]}]}`
+
+	plan, err := parseWorkoutPlan(raw, 3, catalog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := plan.Weeks[0].Days[0].Exercises
+	if len(got) != 2 {
+		t.Fatalf("expected unknown exercise dropped, got %+v", got)
+	}
+	if got[0].ExerciseID != 1 || got[0].Name != "Barbell Squat" || got[1].ExerciseID != 2 || got[1].Name != "Plank" {
+		t.Errorf("exercises not resolved against catalog: %+v", got)
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gymapp/internal/config"
+)
+
+// Prompt template names. Each is a directory of versions, e.g.
+// prompts/recipes/v1.tmpl.
+const (
+	promptRecipes      = "recipes"
+	promptTrainingPlan = "training_plan"
+)
+
+//go:embed prompts
+var embeddedPrompts embed.FS
+
+// promptVersionName is what a version may be called. The name is saved as
+// prompt_version, a VARCHAR(50).
+var promptVersionName = regexp.MustCompile(`^[A-Za-z0-9._-]{1,50}$`)
+
+// promptFuncs are available to every template.
+var promptFuncs = template.FuncMap{
+	"diet":    dietSection,
+	"catalog": catalogSection,
+}
+
+// promptSet holds every version of the prompt templates and the weighted
+// versions each prompt is rendered with.
+type promptSet struct {
+	templates map[string]map[string]*template.Template
+	variants  map[string][]promptVariant
+}
+
+type promptVariant struct {
+	version string
+	weight  int
+}
+
+// loadPrompts parses the embedded templates, then those in cfg.PromptDir,
+// which replace embedded versions of the same name and may add new ones.
+// It fails when cfg.PromptDir is set but is not a directory, or when a
+// selected version does not exist.
+func loadPrompts(cfg *config.AIConfig) (*promptSet, error) {
+	p := &promptSet{
+		templates: make(map[string]map[string]*template.Template),
+		variants:  make(map[string][]promptVariant),
+	}
+
+	embedded, err := fs.Sub(embeddedPrompts, "prompts")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.parse(embedded); err != nil {
+		return nil, err
+	}
+
+	if cfg.PromptDir != "" {
+		info, err := os.Stat(cfg.PromptDir)
+		if err != nil {
+			return nil, fmt.Errorf("prompt directory: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("prompt directory %s is not a directory", cfg.PromptDir)
+		}
+		if err := p.parse(os.DirFS(cfg.PromptDir)); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, spec := range map[string]string{
+		promptRecipes:      cfg.RecipePrompt,
+		promptTrainingPlan: cfg.TrainingPrompt,
+	} {
+		variants, err := parsePromptVariants(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s prompt selection %q: %w", name, spec, err)
+		}
+		for _, v := range variants {
+			if p.templates[name][v.version] == nil {
+				return nil, fmt.Errorf("%s prompt version %q not found", name, v.version)
+			}
+		}
+		p.variants[name] = variants
+	}
+
+	return p, nil
+}
+
+func (p *promptSet) parse(fsys fs.FS) error {
+	paths, err := fs.Glob(fsys, "*/*.tmpl")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range paths {
+		version := strings.TrimSuffix(path.Base(file), ".tmpl")
+		if !promptVersionName.MatchString(version) {
+			return fmt.Errorf("invalid prompt version name %s: use up to 50 letters, digits, '.', '_' or '-'", file)
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt %s: %w", file, err)
+		}
+
+		tmpl, err := template.New(file).Funcs(promptFuncs).Option("missingkey=error").Parse(string(data))
+		if err != nil {
+			return fmt.Errorf("failed to parse prompt %s: %w", file, err)
+		}
+
+		name := path.Dir(file)
+		if p.templates[name] == nil {
+			p.templates[name] = make(map[string]*template.Template)
+		}
+		p.templates[name][version] = tmpl
+	}
+
+	return nil
+}
+
+// parsePromptVariants reads a selection such as "v1" or "v1:90,v2:10".
+// Versions without a weight get 1, and each version may appear once.
+func parsePromptVariants(spec string) ([]promptVariant, error) {
+	var variants []promptVariant
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(spec, ",") {
+		version, weight, hasWeight := strings.Cut(strings.TrimSpace(field), ":")
+		if version == "" {
+			return nil, fmt.Errorf("empty version")
+		}
+		if !promptVersionName.MatchString(version) {
+			return nil, fmt.Errorf("invalid version name %s", version)
+		}
+		if seen[version] {
+			return nil, fmt.Errorf("version %s is listed twice", version)
+		}
+		seen[version] = true
+
+		v := promptVariant{version: version, weight: 1}
+		if hasWeight {
+			n, err := strconv.Atoi(weight)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("weight of %s must be a positive integer", version)
+			}
+			v.weight = n
+		}
+		variants = append(variants, v)
+	}
+
+	sort.Slice(variants, func(i, j int) bool { return variants[i].version < variants[j].version })
+	return variants, nil
+}
+
+// pick chooses the version of a prompt for a user in proportion to the
+// weights. The choice hashes the user ID, so a user keeps getting the same
+// version for as long as the selection is unchanged.
+func (p *promptSet) pick(name string, userID int64) string {
+	variants := p.variants[name]
+
+	total := 0
+	for _, v := range variants {
+		total += v.weight
+	}
+
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", name, userID)
+	n := int(h.Sum32() % uint32(total))
+
+	for _, v := range variants {
+		if n < v.weight {
+			return v.version
+		}
+		n -= v.weight
+	}
+	return variants[len(variants)-1].version
+}
+
+// render executes the user's version of a prompt and returns it with the
+// version used.
+func (p *promptSet) render(name string, userID int64, data any) (string, string, error) {
+	version := p.pick(name, userID)
+
+	var b bytes.Buffer
+	if err := p.templates[name][version].Execute(&b, data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s prompt %s: %w", name, version, err)
+	}
+
+	return strings.TrimSpace(b.String()), version, nil
+}
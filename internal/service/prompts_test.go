@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+)
+
+func TestEmbeddedPromptsRender(t *testing.T) {
+	prompts, err := loadPrompts(&config.AIConfig{RecipePrompt: "v1", TrainingPrompt: "v1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recipe, version, err := prompts.render(promptRecipes, 1, &domain.RecipeInput{
+		Ingredients: "eggs, spinach",
+		Goal:        "cut",
+		Diet:        domain.DietaryPreferences{Allergens: []string{"peanut"}},
+	})
+	if err != nil || version != "v1" {
+		t.Fatalf("got version %q, error %v", version, err)
+	}
+	for _, want := range []string{"ingredients: eggs, spinach\n", "fitness goal of: cut\n", "allergic to: peanut"} {
+		if !strings.Contains(recipe, want) {
+			t.Errorf("recipe prompt is missing %q:\n%s", want, recipe)
+		}
+	}
+
+	plan, _, err := prompts.render(promptTrainingPlan, 1, &domain.TrainingPlanInput{
+		WeightKg: 88.5, HeightCm: 180, TargetWeight: 80, AvailableDays: 3,
+		Trend: &domain.WeightTrend{Samples: 3, DaysSpan: 14, ChangeKg: -2, KgPerWeek: -1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Weight=88.5kg", "Recent trend: -1.00kg/week (-2.0kg over 14 days", "exactly 3 training days"} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("training prompt is missing %q:\n%s", want, plan)
+		}
+	}
+}
+
+func TestPromptOverridesAndSelection(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, promptRecipes), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, promptRecipes, "v2.tmpl"), []byte("Suggest recipes with {{.Ingredients}}.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.AIConfig{PromptDir: dir, RecipePrompt: "v1:1, v2:1", TrainingPrompt: "v1"}
+	prompts, err := loadPrompts(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for userID := int64(1); userID <= 50; userID++ {
+		version := prompts.pick(promptRecipes, userID)
+		if again := prompts.pick(promptRecipes, userID); again != version {
+			t.Fatalf("user %d got %s, then %s", userID, version, again)
+		}
+		seen[version] = true
+	}
+	if !seen["v1"] || !seen["v2"] {
+		t.Errorf("expected both versions to be picked, got %v", seen)
+	}
+
+	backend := &countingBackend{reply: `{"recipes": []}`}
+	svc := NewAIService(backend, prompts)
+	for userID := int64(1); userID <= 50; userID++ {
+		if prompts.pick(promptRecipes, userID) != "v2" {
+			continue
+		}
+		input := &domain.RecipeInput{UserID: userID, Ingredients: "eggs"}
+		if _, err := svc.GenerateRecipes(context.Background(), input); err != nil || input.PromptVersion != "v2" {
+			t.Errorf("got version %q, error %v; want v2", input.PromptVersion, err)
+		}
+		break
+	}
+
+	for _, spec := range []string{"v3", "v1:0", "v1,,v2", "v1:x", "v1:50,v1:50", "v1,v2,v1", "v 1", strings.Repeat("v", 51)} {
+		cfg.RecipePrompt = spec
+		if _, err := loadPrompts(cfg); err == nil {
+			t.Errorf("expected an error for selection %q", spec)
+		}
+	}
+	cfg.RecipePrompt = "v1"
+
+	long := filepath.Join(dir, promptRecipes, strings.Repeat("v", 51)+".tmpl")
+	if err := os.WriteFile(long, []byte("{{.Ingredients}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadPrompts(cfg); err == nil {
+		t.Error("expected an error for a version name longer than 50 characters")
+	}
+	os.Remove(long)
+
+	for _, missing := range []string{filepath.Join(dir, "missing"), filepath.Join(dir, promptRecipes, "v2.tmpl")} {
+		cfg.PromptDir = missing
+		if _, err := loadPrompts(cfg); err == nil {
+			t.Errorf("expected an error for prompt directory %s", missing)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"gymapp/internal/domain"
+)
+
+// The length limits match the recipe_dishes and recipe_dish_ingredients
+// columns.
+const (
+	maxDishServings       = 20
+	maxDishPrepTime       = 600
+	maxDishCalories       = 5000
+	maxDishMacroGrams     = 500
+	maxDishNameLen        = 255
+	maxIngredientUnitLen  = 50
+	maxIngredientQuantity = 100000
+)
+
+type recipeReply struct {
+	Recipes []domain.Dish `json:"recipes"`
+}
+
+// parseDishes decodes model output into dishes and repairs what can be
+// repaired: numbers are clamped, blank ingredients and steps are dropped,
+// and missing calories are derived from macros. Dishes without a name or
+// ingredients are discarded; it fails if none are left.
+func parseDishes(raw string) ([]domain.Dish, error) {
+	var reply recipeReply
+	if err := json.Unmarshal([]byte(extractJSONObject(raw)), &reply); err != nil {
+		return nil, fmt.Errorf("recipes are not valid JSON: %w", err)
+	}
+
+	dishes := make([]domain.Dish, 0, len(reply.Recipes))
+	for _, dish := range reply.Recipes {
+		if repairDish(&dish) {
+			dishes = append(dishes, dish)
+		}
+	}
+
+	if len(dishes) == 0 {
+		return nil, fmt.Errorf("no valid recipes in response")
+	}
+
+	return dishes, nil
+}
+
+// repairDish normalizes a dish in place and reports whether it is usable.
+func repairDish(dish *domain.Dish) bool {
+	dish.ID = 0
+	dish.Flags = nil
+	dish.Name = truncateRunes(strings.TrimSpace(dish.Name), maxDishNameLen)
+	dish.Ingredients = repairIngredients(dish.Ingredients)
+	if dish.Name == "" || len(dish.Ingredients) == 0 {
+		return false
+	}
+
+	dish.Steps = repairSteps(dish.Steps)
+	dish.Servings = clamp(dish.Servings, 1, maxDishServings)
+	dish.PrepTimeMinutes = clamp(dish.PrepTimeMinutes, 0, maxDishPrepTime)
+	dish.ProteinGrams = clampGrams(dish.ProteinGrams)
+	dish.CarbsGrams = clampGrams(dish.CarbsGrams)
+	dish.FatGrams = clampGrams(dish.FatGrams)
+
+	if dish.Calories <= 0 {
+		dish.Calories = int(math.Round(4*dish.ProteinGrams + 4*dish.CarbsGrams + 9*dish.FatGrams))
+	}
+	dish.Calories = clamp(dish.Calories, 0, maxDishCalories)
+
+	return true
+}
+
+func repairIngredients(ingredients []domain.DishIngredient) []domain.DishIngredient {
+	out := make([]domain.DishIngredient, 0, len(ingredients))
+	for _, ing := range ingredients {
+		ing.Name = truncateRunes(strings.ToLower(strings.TrimSpace(ing.Name)), maxDishNameLen)
+		if ing.Name == "" {
+			continue
+		}
+		if ing.Quantity < 0 || math.IsNaN(ing.Quantity) {
+			ing.Quantity = 0
+		}
+		ing.Quantity = math.Min(ing.Quantity, maxIngredientQuantity)
+		ing.Unit = truncateRunes(strings.ToLower(strings.TrimSpace(ing.Unit)), maxIngredientUnitLen)
+		out = append(out, ing)
+	}
+	return out
+}
+
+func repairSteps(steps []string) []string {
+	out := make([]string, 0, len(steps))
+	for _, step := range steps {
+		if step = strings.TrimSpace(step); step != "" {
+			out = append(out, step)
+		}
+	}
+	return out
+}
+
+func clampGrams(v float64) float64 {
+	if v < 0 || math.IsNaN(v) {
+		return 0
+	}
+	return math.Min(v, maxDishMacroGrams)
+}
+
+// truncateRunes cuts s to at most n characters, trimming any space left at
+// the end.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n]))
+}
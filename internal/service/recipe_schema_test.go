@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDishes(t *testing.T) {
+	raw := `{"recipes": [
+		{"name": " Omelette ", "servings": 0, "protein_g": 20, "carbs_g": 2, "fat_g": 15,
+		 "ingredients": [{"name": "Eggs", "quantity": 3, "unit": "PCS"}, {"name": " "}],
+		 "steps": ["Whisk", "", "Cook"]},
+		{"name": "Nothing", "ingredients": []}
+	]}`
+
+	dishes, err := parseDishes(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(dishes) != 1 {
+		t.Fatalf("expected 1 dish, got %d", len(dishes))
+	}
+
+	d := dishes[0]
+	if d.Name != "Omelette" || d.Servings != 1 || len(d.Steps) != 2 {
+		t.Errorf("dish not repaired: %+v", d)
+	}
+	if len(d.Ingredients) != 1 || d.Ingredients[0].Name != "eggs" || d.Ingredients[0].Unit != "pcs" {
+		t.Errorf("ingredients not repaired: %+v", d.Ingredients)
+	}
+	if d.Calories != 223 {
+		t.Errorf("expected calories derived from macros, got %d", d.Calories)
+	}
+
+	if _, err := parseDishes(`{"recipes": []}`); err == nil {
+		t.Error("expected error for empty recipes")
+	}
+}
+
+func TestParseDishesFitsColumns(t *testing.T) {
+	name := strings.Repeat("é", 300)
+	raw := `{"recipes": [{"name": "` + name + `", "ingredients": [
+		{"name": "` + name + `", "quantity": 1e12, "unit": "` + strings.Repeat("u", 80) + `"}
+	]}]}`
+
+	dishes, err := parseDishes(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d := dishes[0]
+	if got := len([]rune(d.Name)); got != maxDishNameLen {
+		t.Errorf("expected the dish name cut to %d characters, got %d", maxDishNameLen, got)
+	}
+	ing := d.Ingredients[0]
+	if len([]rune(ing.Name)) != maxDishNameLen || len(ing.Unit) != maxIngredientUnitLen {
+		t.Errorf("ingredient not cut to fit: name %d, unit %d characters", len([]rune(ing.Name)), len(ing.Unit))
+	}
+	if ing.Quantity != maxIngredientQuantity {
+		t.Errorf("expected quantity clamped to %d, got %v", maxIngredientQuantity, ing.Quantity)
+	}
+}
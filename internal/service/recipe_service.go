@@ -2,64 +2,178 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"gymapp/internal/domain"
 )
 
+const maxIngredientsLength = 2000
+
+var supportedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
 type RecipeService struct {
 	recipeRepo domain.RecipeRepository
-	aiService  *AIService
+	userRepo   domain.UserRepository
+	nutrition  domain.NutritionService
+	aiProvider domain.AIProvider
+	usage      domain.UsageService
 }
 
-func NewRecipeService(recipeRepo domain.RecipeRepository, aiService *AIService) *RecipeService {
+func NewRecipeService(
+	recipeRepo domain.RecipeRepository,
+	userRepo domain.UserRepository,
+	nutrition domain.NutritionService,
+	aiProvider domain.AIProvider,
+	usage domain.UsageService,
+) *RecipeService {
 	return &RecipeService{
 		recipeRepo: recipeRepo,
-		aiService:  aiService,
+		userRepo:   userRepo,
+		nutrition:  nutrition,
+		aiProvider: aiProvider,
+		usage:      usage,
 	}
 }
 
+// ValidateTextRequest normalizes and checks a request, and the user's AI
+// quota, before it is queued.
+func (s *RecipeService) ValidateTextRequest(ctx context.Context, userID int64, req *domain.GenerateRecipeRequest) error {
+	req.Ingredients = strings.TrimSpace(req.Ingredients)
+	if req.Ingredients == "" {
+		return fmt.Errorf("%w: ingredients cannot be empty", domain.ErrInvalidInput)
+	}
+
+	if len(req.Ingredients) > maxIngredientsLength {
+		return fmt.Errorf("%w: ingredients must be at most %d characters", domain.ErrInvalidInput, maxIngredientsLength)
+	}
+
+	return s.usage.CheckQuota(ctx, userID)
+}
+
 func (s *RecipeService) GenerateFromText(ctx context.Context, userID int64, ingredients string) (*domain.Recipe, error) {
-	if ingredients == "" {
-		return nil, fmt.Errorf("ingredients cannot be empty")
+	return s.StreamFromText(ctx, userID, &domain.GenerateRecipeRequest{Ingredients: ingredients}, nil)
+}
+
+// StreamFromText generates recipes like GenerateFromText while passing the
+// model's reply to stream as it is written. The recipe is saved once the
+// reply is complete.
+func (s *RecipeService) StreamFromText(ctx context.Context, userID int64, req *domain.GenerateRecipeRequest, stream domain.StreamFunc) (_ *domain.Recipe, err error) {
+	if err := s.ValidateTextRequest(ctx, userID, req); err != nil {
+		return nil, err
 	}
 
-	aiResponse, err := s.aiService.GenerateRecipes(ctx, ingredients)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate recipes: %w", err)
+		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	recipe := &domain.Recipe{
-		UserID:      userID,
-		Ingredients: ingredients,
-		AIResponse:  aiResponse,
+	usage := &domain.TokenUsage{}
+	defer func() { s.usage.RecordAttempt(ctx, userID, domain.UsageRecipeText, *usage, err) }()
+
+	return s.generate(ctx, user, &domain.RecipeInput{
+		Ingredients: req.Ingredients,
+		Stream:      stream,
+		SkipCache:   req.NoCache,
+		Usage:       usage,
+	})
+}
+
+// RunTextJob is the JobHandler for domain.JobKindRecipe.
+func (s *RecipeService) RunTextJob(ctx context.Context, userID int64, payload json.RawMessage) (int64, error) {
+	var req domain.GenerateRecipeRequest
+	if err := decodeJobPayload(payload, &req); err != nil {
+		return 0, err
 	}
 
-	if err := s.recipeRepo.Create(ctx, recipe); err != nil {
-		return nil, fmt.Errorf("failed to save recipe: %w", err)
+	recipe, err := s.StreamFromText(ctx, userID, &req, nil)
+	if err != nil {
+		return 0, err
 	}
 
-	return recipe, nil
+	return recipe.ID, nil
 }
 
-func (s *RecipeService) GenerateFromImage(ctx context.Context, userID int64, imageData []byte) (*domain.Recipe, error) {
+func (s *RecipeService) GenerateFromImage(ctx context.Context, userID int64, imageData []byte) (_ *domain.Recipe, err error) {
 	if len(imageData) == 0 {
-		return nil, fmt.Errorf("image data cannot be empty")
+		return nil, fmt.Errorf("%w: image data cannot be empty", domain.ErrInvalidInput)
+	}
+
+	mimeType := http.DetectContentType(imageData)
+	if !supportedImageTypes[mimeType] {
+		return nil, fmt.Errorf("%w: unsupported image type %s", domain.ErrInvalidInput, mimeType)
+	}
+
+	if err := s.usage.CheckQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	// TODO: Implement image recognition via AI API
-	// For now, use a placeholder
-	ingredientsList := "Recipe generated from image"
+	// Recognition and generation are accounted together as one request.
+	usage := &domain.TokenUsage{}
+	defer func() { s.usage.RecordAttempt(ctx, userID, domain.UsageRecipeImage, *usage, err) }()
 
-	aiResponse, err := s.aiService.GenerateRecipes(ctx, ingredientsList)
+	detected, err := s.aiProvider.DetectIngredients(ctx, imageData, mimeType, usage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recognize ingredients: %w", err)
+	}
+
+	if len(detected) == 0 {
+		return nil, fmt.Errorf("%w: no ingredients recognized in image", domain.ErrInvalidInput)
+	}
+
+	return s.generate(ctx, user, &domain.RecipeInput{
+		Ingredients: strings.Join(detected, ", "),
+		Usage:       usage,
+	})
+}
+
+// generate completes input with the user's goal, targets and diet, asks the
+// provider for recipes, drops dishes that contain the user's allergens,
+// flags other dietary conflicts and saves the result. The tokens spent are
+// added to input.Usage for the caller to record.
+func (s *RecipeService) generate(ctx context.Context, user *domain.User, input *domain.RecipeInput) (*domain.Recipe, error) {
+	targets, err := optionalTargets(ctx, s.nutrition, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	input.UserID = user.ID
+	input.Goal = user.Goal
+	input.Targets = targets
+	input.Diet = user.Diet
+	input.Validate = func(reply string) error {
+		_, err := replyDishes(reply, user.Diet)
+		return err
+	}
+
+	aiResponse, err := s.aiProvider.GenerateRecipes(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recipes: %w", err)
+	}
+
+	dishes, err := replyDishes(aiResponse, user.Diet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate recipes: %w", err)
 	}
 
 	recipe := &domain.Recipe{
-		UserID:      userID,
-		Ingredients: ingredientsList,
-		AIResponse:  aiResponse,
+		UserID:        user.ID,
+		Ingredients:   input.Ingredients,
+		AIResponse:    aiResponse,
+		PromptVersion: input.PromptVersion,
+		Dishes:        dishes,
 	}
 
 	if err := s.recipeRepo.Create(ctx, recipe); err != nil {
@@ -69,6 +183,65 @@ func (s *RecipeService) GenerateFromImage(ctx context.Context, userID int64, ima
 	return recipe, nil
 }
 
+// replyDishes parses a recipes reply and screens the dishes against diet.
+func replyDishes(reply string, diet domain.DietaryPreferences) ([]domain.Dish, error) {
+	dishes, err := parseDishes(reply)
+	if err != nil {
+		return nil, err
+	}
+	return screenDishes(diet, dishes)
+}
+
 func (s *RecipeService) GetHistory(ctx context.Context, userID int64, limit, offset int) ([]*domain.Recipe, error) {
 	return s.recipeRepo.GetByUserID(ctx, userID, limit, offset)
 }
+
+func (s *RecipeService) Get(ctx context.Context, userID, id int64) (*domain.Recipe, error) {
+	return s.recipeRepo.GetByID(ctx, id, userID)
+}
+
+func (s *RecipeService) Delete(ctx context.Context, userID, id int64) error {
+	return s.recipeRepo.Delete(ctx, id, userID)
+}
+
+// SetFavorite adds or removes the recipe from the user's favorites and
+// returns the updated recipe.
+func (s *RecipeService) SetFavorite(ctx context.Context, userID, id int64, favorite bool) (*domain.Recipe, error) {
+	recipe, err := s.recipeRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if favorite {
+		err = s.recipeRepo.AddFavorite(ctx, id, userID)
+	} else {
+		err = s.recipeRepo.RemoveFavorite(ctx, id, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	recipe.IsFavorite = favorite
+	return recipe, nil
+}
+
+func (s *RecipeService) GetFavorites(ctx context.Context, userID int64, limit, offset int) ([]*domain.Recipe, error) {
+	return s.recipeRepo.GetFavorites(ctx, userID, limit, offset)
+}
+
+func (s *RecipeService) Search(ctx context.Context, userID int64, filter *domain.RecipeSearchFilter) ([]*domain.RecipeSearchResult, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query == "" {
+		return nil, fmt.Errorf("%w: q is required", domain.ErrInvalidInput)
+	}
+
+	if filter.MinCalories < 0 || filter.MaxCalories < 0 || filter.MaxPrepMinutes < 0 {
+		return nil, fmt.Errorf("%w: filters must not be negative", domain.ErrInvalidInput)
+	}
+
+	if filter.MaxCalories > 0 && filter.MinCalories > filter.MaxCalories {
+		return nil, fmt.Errorf("%w: min_calories must not exceed max_calories", domain.ErrInvalidInput)
+	}
+
+	return s.recipeRepo.Search(ctx, userID, filter)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gymapp/internal/domain"
+)
+
+type fakeRecipeRepo struct {
+	recipes   []*domain.Recipe
+	favorites map[int64]bool
+	searched  *domain.RecipeSearchFilter
+}
+
+func (r *fakeRecipeRepo) Create(ctx context.Context, recipe *domain.Recipe) error {
+	recipe.ID = int64(len(r.recipes) + 1)
+	r.recipes = append(r.recipes, recipe)
+	return nil
+}
+
+func (r *fakeRecipeRepo) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.Recipe, error) {
+	var out []*domain.Recipe
+	for _, recipe := range r.recipes {
+		if recipe.UserID == userID {
+			out = append(out, recipe)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRecipeRepo) GetByID(ctx context.Context, id, userID int64) (*domain.Recipe, error) {
+	for _, recipe := range r.recipes {
+		if recipe.ID == id && recipe.UserID == userID {
+			copied := *recipe
+			copied.IsFavorite = r.favorites[id]
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("recipe %w", domain.ErrNotFound)
+}
+
+func (r *fakeRecipeRepo) Delete(ctx context.Context, id, userID int64) error {
+	for i, recipe := range r.recipes {
+		if recipe.ID == id && recipe.UserID == userID {
+			r.recipes = append(r.recipes[:i], r.recipes[i+1:]...)
+			delete(r.favorites, id)
+			return nil
+		}
+	}
+	return fmt.Errorf("recipe %w", domain.ErrNotFound)
+}
+
+func (r *fakeRecipeRepo) AddFavorite(ctx context.Context, id, userID int64) error {
+	if r.favorites == nil {
+		r.favorites = make(map[int64]bool)
+	}
+	r.favorites[id] = true
+	return nil
+}
+
+func (r *fakeRecipeRepo) RemoveFavorite(ctx context.Context, id, userID int64) error {
+	delete(r.favorites, id)
+	return nil
+}
+
+func (r *fakeRecipeRepo) GetFavorites(ctx context.Context, userID int64, limit, offset int) ([]*domain.Recipe, error) {
+	var out []*domain.Recipe
+	for _, recipe := range r.recipes {
+		if recipe.UserID == userID && r.favorites[recipe.ID] {
+			out = append(out, recipe)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRecipeRepo) Search(ctx context.Context, userID int64, filter *domain.RecipeSearchFilter) ([]*domain.RecipeSearchResult, error) {
+	r.searched = filter
+	return nil, nil
+}
+
+func newTestRecipeService(recipes *fakeRecipeRepo) *RecipeService {
+	users := &fakeUserRepo{users: map[int64]*domain.User{1: {ID: 1}, 2: {ID: 2}}}
+	return NewRecipeService(recipes, users, NewNutritionService(users, &fakeMeasurementRepo{}),
+		&fakeAIProvider{}, newTestUsageService(0, 0))
+}
+
+func TestRecipeFavorites(t *testing.T) {
+	recipes := &fakeRecipeRepo{recipes: []*domain.Recipe{
+		{ID: 1, UserID: 1, Ingredients: "eggs"},
+		{ID: 2, UserID: 1, Ingredients: "rice"},
+		{ID: 3, UserID: 2, Ingredients: "tofu"},
+	}}
+	svc := newTestRecipeService(recipes)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		recipe, err := svc.SetFavorite(ctx, 1, 2, true)
+		if err != nil || !recipe.IsFavorite {
+			t.Fatalf("favorite %d: got %+v, %v", i+1, recipe, err)
+		}
+	}
+
+	favorites, err := svc.GetFavorites(ctx, 1, 20, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(favorites) != 1 || favorites[0].ID != 2 {
+		t.Errorf("expected recipe 2 as the only favorite, got %+v", favorites)
+	}
+
+	if _, err := svc.SetFavorite(ctx, 1, 3, true); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found when favoriting another user's recipe, got %v", err)
+	}
+	if recipes.favorites[3] {
+		t.Error("another user's recipe should not be favorited")
+	}
+
+	for i := 0; i < 2; i++ {
+		recipe, err := svc.SetFavorite(ctx, 1, 2, false)
+		if err != nil || recipe.IsFavorite {
+			t.Fatalf("unfavorite %d: got %+v, %v", i+1, recipe, err)
+		}
+	}
+
+	if favorites, _ := svc.GetFavorites(ctx, 1, 20, 0); len(favorites) != 0 {
+		t.Errorf("expected no favorites left, got %+v", favorites)
+	}
+}
+
+func TestRecipeDeleteChecksOwner(t *testing.T) {
+	recipes := &fakeRecipeRepo{recipes: []*domain.Recipe{
+		{ID: 1, UserID: 1, Ingredients: "eggs"},
+		{ID: 2, UserID: 2, Ingredients: "tofu"},
+	}}
+	svc := newTestRecipeService(recipes)
+	ctx := context.Background()
+
+	if err := svc.Delete(ctx, 1, 2); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found when deleting another user's recipe, got %v", err)
+	}
+	if _, err := svc.Get(ctx, 2, 2); err != nil {
+		t.Errorf("another user's recipe should survive, got %v", err)
+	}
+
+	if err := svc.Delete(ctx, 1, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Get(ctx, 1, 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected the deleted recipe to be gone, got %v", err)
+	}
+	if err := svc.Delete(ctx, 1, 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found when deleting twice, got %v", err)
+	}
+}
+
+func TestRecipeSearchValidatesFilter(t *testing.T) {
+	recipes := &fakeRecipeRepo{}
+	svc := newTestRecipeService(recipes)
+	ctx := context.Background()
+
+	for _, filter := range []domain.RecipeSearchFilter{
+		{Query: "  "},
+		{Query: "eggs", MinCalories: -1},
+		{Query: "eggs", MaxPrepMinutes: -5},
+		{Query: "eggs", MinCalories: 800, MaxCalories: 400},
+	} {
+		if _, err := svc.Search(ctx, 1, &filter); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("expected invalid input for %+v, got %v", filter, err)
+		}
+	}
+	if recipes.searched != nil {
+		t.Fatal("invalid filters should not reach the repository")
+	}
+
+	if _, err := svc.Search(ctx, 1, &domain.RecipeSearchFilter{Query: " eggs -spinach ", MinCalories: 400}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recipes.searched == nil || recipes.searched.Query != "eggs -spinach" {
+		t.Errorf("expected the trimmed query to be searched, got %+v", recipes.searched)
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gymapp/internal/domain"
+)
+
+const (
+	defaultShoppingListName = "Shopping list"
+	maxShoppingListName     = 255
+	maxShoppingListSources  = 50
+)
+
+type unitConversion struct {
+	unit   string
+	factor float64
+}
+
+// unitConversions maps the units models commonly emit to the base unit of
+// their family. Units not listed are kept as written and only merge with
+// the same unit.
+var unitConversions = map[string]unitConversion{
+	"g": {"g", 1}, "gr": {"g", 1}, "gram": {"g", 1}, "grams": {"g", 1},
+	"kg": {"g", 1000}, "kilogram": {"g", 1000}, "kilograms": {"g", 1000},
+	"mg": {"g", 0.001},
+	"oz": {"g", 28.35}, "ounce": {"g", 28.35}, "ounces": {"g", 28.35},
+	"lb": {"g", 453.59}, "lbs": {"g", 453.59}, "pound": {"g", 453.59}, "pounds": {"g", 453.59},
+
+	"ml": {"ml", 1}, "milliliter": {"ml", 1}, "milliliters": {"ml", 1}, "millilitre": {"ml", 1}, "millilitres": {"ml", 1},
+	"l": {"ml", 1000}, "liter": {"ml", 1000}, "liters": {"ml", 1000}, "litre": {"ml", 1000}, "litres": {"ml", 1000},
+	"tsp": {"ml", 5}, "teaspoon": {"ml", 5}, "teaspoons": {"ml", 5},
+	"tbsp": {"ml", 15}, "tablespoon": {"ml", 15}, "tablespoons": {"ml", 15},
+	"cup": {"ml", 240}, "cups": {"ml", 240},
+	"fl oz": {"ml", 29.57},
+
+	"": {"pcs", 1}, "pcs": {"pcs", 1}, "pc": {"pcs", 1}, "piece": {"pcs", 1}, "pieces": {"pcs", 1},
+	"whole": {"pcs", 1}, "unit": {"pcs", 1}, "units": {"pcs", 1},
+}
+
+type ShoppingListService struct {
+	listRepo     domain.ShoppingListRepository
+	recipeRepo   domain.RecipeRepository
+	mealPlanRepo domain.MealPlanRepository
+}
+
+func NewShoppingListService(
+	listRepo domain.ShoppingListRepository,
+	recipeRepo domain.RecipeRepository,
+	mealPlanRepo domain.MealPlanRepository,
+) *ShoppingListService {
+	return &ShoppingListService{
+		listRepo:     listRepo,
+		recipeRepo:   recipeRepo,
+		mealPlanRepo: mealPlanRepo,
+	}
+}
+
+// Create builds a list from the ingredients of the selected recipe dishes
+// and meal plans, merging duplicates after unit normalization.
+func (s *ShoppingListService) Create(ctx context.Context, userID int64, req *domain.CreateShoppingListRequest) (*domain.ShoppingList, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = defaultShoppingListName
+	}
+	if len(name) > maxShoppingListName {
+		return nil, fmt.Errorf("%w: name must be at most %d characters", domain.ErrInvalidInput, maxShoppingListName)
+	}
+
+	sources := len(req.Recipes) + len(req.MealPlanIDs)
+	if sources == 0 {
+		return nil, fmt.Errorf("%w: select at least one recipe or meal plan", domain.ErrInvalidInput)
+	}
+	if sources > maxShoppingListSources {
+		return nil, fmt.Errorf("%w: at most %d recipes and meal plans per list", domain.ErrInvalidInput, maxShoppingListSources)
+	}
+
+	var ingredients []domain.DishIngredient
+	for _, src := range req.Recipes {
+		dishIngredients, err := s.recipeIngredients(ctx, userID, src)
+		if err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, dishIngredients...)
+	}
+
+	for _, planID := range req.MealPlanIDs {
+		plan, err := s.mealPlanRepo.GetByID(ctx, planID, userID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, fmt.Errorf("%w: unknown meal_plan_id %d", domain.ErrInvalidInput, planID)
+			}
+			return nil, err
+		}
+		for _, slot := range plan.Slots {
+			ingredients = append(ingredients, slot.Dish.Ingredients...)
+		}
+	}
+
+	list := &domain.ShoppingList{
+		UserID: userID,
+		Name:   name,
+		Items:  aggregateIngredients(ingredients),
+	}
+
+	if err := s.listRepo.Create(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to save shopping list: %w", err)
+	}
+
+	return list, nil
+}
+
+func (s *ShoppingListService) List(ctx context.Context, userID int64, limit, offset int) ([]*domain.ShoppingList, error) {
+	return s.listRepo.GetByUserID(ctx, userID, limit, offset)
+}
+
+func (s *ShoppingListService) Get(ctx context.Context, userID, id int64) (*domain.ShoppingList, error) {
+	return s.listRepo.GetByID(ctx, id, userID)
+}
+
+func (s *ShoppingListService) Delete(ctx context.Context, userID, id int64) error {
+	return s.listRepo.Delete(ctx, id, userID)
+}
+
+// CheckItem marks an item as bought or not and returns the updated list.
+func (s *ShoppingListService) CheckItem(ctx context.Context, userID, listID, itemID int64, checked bool) (*domain.ShoppingList, error) {
+	if err := s.listRepo.SetItemChecked(ctx, listID, itemID, userID, checked); err != nil {
+		return nil, err
+	}
+
+	return s.listRepo.GetByID(ctx, listID, userID)
+}
+
+// Export renders the list as plain text or a Markdown task list.
+func (s *ShoppingListService) Export(ctx context.Context, userID, id int64, format string) (string, error) {
+	if format == "" {
+		format = domain.ShoppingListFormatText
+	}
+	if format != domain.ShoppingListFormatText && format != domain.ShoppingListFormatMarkdown {
+		return "", fmt.Errorf("%w: format must be text or markdown", domain.ErrInvalidInput)
+	}
+
+	list, err := s.listRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return renderShoppingList(list, format), nil
+}
+
+func (s *ShoppingListService) recipeIngredients(ctx context.Context, userID int64, src domain.ShoppingListRecipe) ([]domain.DishIngredient, error) {
+	recipe, err := s.recipeRepo.GetByID(ctx, src.RecipeID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown recipe_id %d", domain.ErrInvalidInput, src.RecipeID)
+		}
+		return nil, err
+	}
+
+	var ingredients []domain.DishIngredient
+	for _, dish := range recipe.Dishes {
+		if src.DishID == 0 || dish.ID == src.DishID {
+			ingredients = append(ingredients, dish.Ingredients...)
+		}
+	}
+
+	if src.DishID != 0 && ingredients == nil {
+		return nil, fmt.Errorf("%w: dish %d is not part of recipe %d", domain.ErrInvalidInput, src.DishID, recipe.ID)
+	}
+
+	return ingredients, nil
+}
+
+// normalizeUnit converts a quantity to the base unit of its family.
+func normalizeUnit(quantity float64, unit string) (float64, string) {
+	unit = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(unit, ".")))
+	if conv, ok := unitConversions[unit]; ok {
+		return quantity * conv.factor, conv.unit
+	}
+	return quantity, unit
+}
+
+// aggregateIngredients merges ingredients with the same name and normalized
+// unit, summing quantities, and sorts the result by name.
+func aggregateIngredients(ingredients []domain.DishIngredient) []domain.ShoppingListItem {
+	type key struct{ name, unit string }
+
+	totals := make(map[key]int)
+	items := []domain.ShoppingListItem{}
+	for _, ing := range ingredients {
+		name := strings.ToLower(strings.TrimSpace(ing.Name))
+		if name == "" {
+			continue
+		}
+
+		quantity, unit := normalizeUnit(max(ing.Quantity, 0), ing.Unit)
+		k := key{name, unit}
+		if i, ok := totals[k]; ok {
+			items[i].Quantity += quantity
+			continue
+		}
+
+		totals[k] = len(items)
+		items = append(items, domain.ShoppingListItem{Name: name, Quantity: quantity, Unit: unit})
+	}
+
+	for i := range items {
+		items[i].Quantity = math.Round(items[i].Quantity*100) / 100
+	}
+
+	slices.SortFunc(items, func(a, b domain.ShoppingListItem) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Unit, b.Unit))
+	})
+
+	return items
+}
+
+func renderShoppingList(list *domain.ShoppingList, format string) string {
+	var b strings.Builder
+	if format == domain.ShoppingListFormatMarkdown {
+		fmt.Fprintf(&b, "# %s\n\n", list.Name)
+	} else {
+		fmt.Fprintf(&b, "%s\n\n", list.Name)
+	}
+
+	for _, item := range list.Items {
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+		if format == domain.ShoppingListFormatMarkdown {
+			box = "- " + box
+		}
+
+		fmt.Fprintf(&b, "%s %s\n", box, formatShoppingItem(item))
+	}
+
+	return b.String()
+}
+
+// formatShoppingItem writes an item as "1.5 kg chicken breast", switching
+// to kg and l above 1000 g and ml and leaving out "to taste" quantities.
+func formatShoppingItem(item domain.ShoppingListItem) string {
+	if item.Quantity == 0 {
+		return item.Name
+	}
+
+	quantity, unit := item.Quantity, item.Unit
+	switch {
+	case unit == "g" && quantity >= 1000:
+		quantity, unit = quantity/1000, "kg"
+	case unit == "ml" && quantity >= 1000:
+		quantity, unit = quantity/1000, "l"
+	}
+
+	q := strconv.FormatFloat(math.Round(quantity*100)/100, 'f', -1, 64)
+	if unit == "pcs" {
+		return q + " x " + item.Name
+	}
+	return q + " " + unit + " " + item.Name
+}
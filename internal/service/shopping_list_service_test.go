@@ -0,0 +1,46 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"gymapp/internal/domain"
+)
+
+func TestAggregateIngredientsNormalizesAndMerges(t *testing.T) {
+	items := aggregateIngredients([]domain.DishIngredient{
+		{Name: "Olive Oil", Quantity: 1, Unit: "tbsp"},
+		{Name: "chicken breast", Quantity: 0.5, Unit: "kg"},
+		{Name: "olive oil", Quantity: 30, Unit: "ml"},
+		{Name: "eggs", Quantity: 2, Unit: ""},
+		{Name: "chicken breast", Quantity: 300, Unit: "grams"},
+		{Name: "eggs", Quantity: 3, Unit: "pcs"},
+		{Name: "salt", Unit: "pinch"},
+	})
+
+	want := []domain.ShoppingListItem{
+		{Name: "chicken breast", Quantity: 800, Unit: "g"},
+		{Name: "eggs", Quantity: 5, Unit: "pcs"},
+		{Name: "olive oil", Quantity: 45, Unit: "ml"},
+		{Name: "salt", Quantity: 0, Unit: "pinch"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("got %+v, want %+v", items, want)
+	}
+}
+
+func TestRenderShoppingListMarkdown(t *testing.T) {
+	list := &domain.ShoppingList{
+		Name: "Week 1",
+		Items: []domain.ShoppingListItem{
+			{Name: "chicken breast", Quantity: 1500, Unit: "g", Checked: true},
+			{Name: "eggs", Quantity: 6, Unit: "pcs"},
+			{Name: "salt", Unit: "pinch"},
+		},
+	}
+
+	want := "# Week 1\n\n- [x] 1.5 kg chicken breast\n- [ ] 6 x eggs\n- [ ] salt\n"
+	if got := renderShoppingList(list, domain.ShoppingListFormatMarkdown); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -2,36 +2,58 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"gymapp/internal/domain"
 )
 
 type TrainingService struct {
-	trainingRepo domain.TrainingRepository
-	userRepo     domain.UserRepository
-	aiService    *AIService
+	trainingRepo    domain.TrainingRepository
+	userRepo        domain.UserRepository
+	measurementRepo domain.BodyMeasurementRepository
+	exerciseRepo    domain.ExerciseRepository
+	nutrition       domain.NutritionService
+	aiProvider      domain.AIProvider
+	usage           domain.UsageService
 }
 
 func NewTrainingService(
 	trainingRepo domain.TrainingRepository,
 	userRepo domain.UserRepository,
-	aiService *AIService,
+	measurementRepo domain.BodyMeasurementRepository,
+	exerciseRepo domain.ExerciseRepository,
+	nutrition domain.NutritionService,
+	aiProvider domain.AIProvider,
+	usage domain.UsageService,
 ) *TrainingService {
 	return &TrainingService{
-		trainingRepo: trainingRepo,
-		userRepo:     userRepo,
-		aiService:    aiService,
+		trainingRepo:    trainingRepo,
+		userRepo:        userRepo,
+		measurementRepo: measurementRepo,
+		exerciseRepo:    exerciseRepo,
+		nutrition:       nutrition,
+		aiProvider:      aiProvider,
+		usage:           usage,
 	}
 }
 
-func (s *TrainingService) GeneratePlan(ctx context.Context, userID int64, req *domain.GeneratePlanRequest) (*domain.TrainingPlan, error) {
+// ValidatePlanRequest checks a request, the user's profile and AI quota
+// before the plan is queued.
+func (s *TrainingService) ValidatePlanRequest(ctx context.Context, userID int64, req *domain.GeneratePlanRequest) error {
+	_, err := s.validatePlanRequest(ctx, userID, req)
+	return err
+}
+
+func (s *TrainingService) validatePlanRequest(ctx context.Context, userID int64, req *domain.GeneratePlanRequest) (*domain.User, error) {
 	if req.AvailableDays <= 0 || req.AvailableDays > 7 {
-		return nil, fmt.Errorf("available_days must be between 1 and 7")
+		return nil, fmt.Errorf("%w: available_days must be between 1 and 7", domain.ErrInvalidInput)
 	}
 
 	if req.TargetWeight <= 0 {
-		return nil, fmt.Errorf("target_weight must be positive")
+		return nil, fmt.Errorf("%w: target_weight must be positive", domain.ErrInvalidInput)
 	}
 
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -39,14 +61,63 @@ func (s *TrainingService) GeneratePlan(ctx context.Context, userID int64, req *d
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	planJSON, err := s.aiService.GenerateTrainingPlan(ctx, user.Weight, req.TargetWeight, user.Height, req.AvailableDays)
+	if !user.HasProfile() {
+		return nil, fmt.Errorf("%w: profile incomplete: set height and weight via /users/me first", domain.ErrInvalidInput)
+	}
+
+	if err := s.usage.CheckQuota(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *TrainingService) GeneratePlan(ctx context.Context, userID int64, req *domain.GeneratePlanRequest) (*domain.TrainingPlan, error) {
+	return s.StreamPlan(ctx, userID, req, nil)
+}
+
+// StreamPlan generates a plan like GeneratePlan while passing the model's
+// reply to stream as it is written. The plan is saved once the reply is
+// complete.
+func (s *TrainingService) StreamPlan(ctx context.Context, userID int64, req *domain.GeneratePlanRequest, stream domain.StreamFunc) (_ *domain.TrainingPlan, err error) {
+	user, err := s.validatePlanRequest(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := s.planInput(ctx, user, req)
+	if err != nil {
+		return nil, err
+	}
+	input.Stream = stream
+	input.SkipCache = req.NoCache
+	input.Usage = &domain.TokenUsage{}
+	defer func() { s.usage.RecordAttempt(ctx, userID, domain.UsageTrainingPlan, *input.Usage, err) }()
+	input.Validate = func(reply string) error {
+		_, err := parseWorkoutPlan(reply, req.AvailableDays, input.Exercises)
+		return err
+	}
+
+	raw, err := s.aiProvider.GenerateTrainingPlan(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate plan: %w", err)
+	}
+
+	workout, err := parseWorkoutPlan(raw, req.AvailableDays, input.Exercises)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate plan: %w", err)
 	}
 
+	planJSON, err := json.Marshal(workout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plan: %w", err)
+	}
+
 	plan := &domain.TrainingPlan{
-		UserID:   userID,
-		PlanJSON: planJSON,
+		UserID:        userID,
+		PlanJSON:      string(planJSON),
+		Plan:          workout,
+		PromptVersion: input.PromptVersion,
 	}
 
 	if err := s.trainingRepo.Create(ctx, plan); err != nil {
@@ -56,6 +127,126 @@ func (s *TrainingService) GeneratePlan(ctx context.Context, userID int64, req *d
 	return plan, nil
 }
 
+// RunPlanJob is the JobHandler for domain.JobKindTrainingPlan.
+func (s *TrainingService) RunPlanJob(ctx context.Context, userID int64, payload json.RawMessage) (int64, error) {
+	var req domain.GeneratePlanRequest
+	if err := decodeJobPayload(payload, &req); err != nil {
+		return 0, err
+	}
+
+	plan, err := s.GeneratePlan(ctx, userID, &req)
+	if err != nil {
+		return 0, err
+	}
+
+	return plan.ID, nil
+}
+
+// planInput prefers the latest logged weight over the profile value, adds
+// the weight trend over the last 30 days and the nutrition targets when
+// there are any, and attaches the exercise catalog the plan must use.
+func (s *TrainingService) planInput(ctx context.Context, user *domain.User, req *domain.GeneratePlanRequest) (*domain.TrainingPlanInput, error) {
+	exercises, err := s.exerciseRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exercise catalog: %w", err)
+	}
+
+	targets, err := optionalTargets(ctx, s.nutrition, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &domain.TrainingPlanInput{
+		UserID:        user.ID,
+		WeightKg:      float64(user.Weight),
+		HeightCm:      user.Height,
+		TargetWeight:  req.TargetWeight,
+		AvailableDays: req.AvailableDays,
+		Targets:       targets,
+		Exercises:     exercises,
+	}
+
+	latest, err := s.measurementRepo.GetLatestWeight(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return input, nil
+		}
+		return nil, fmt.Errorf("failed to load measurements: %w", err)
+	}
+	input.WeightKg = latest.WeightKg
+
+	to := time.Now().Unix()
+	recent, err := s.measurementRepo.GetRange(ctx, user.ID, to-int64(trendWindow.Seconds()), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load measurements: %w", err)
+	}
+	input.Trend = computeWeightTrend(recent)
+
+	return input, nil
+}
+
 func (s *TrainingService) GetLatest(ctx context.Context, userID int64) (*domain.TrainingPlan, error) {
-	return s.trainingRepo.GetLatestByUserID(ctx, userID)
+	plan, err := s.trainingRepo.GetLatestByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	decodePlan(plan)
+	return plan, nil
+}
+
+func (s *TrainingService) GetActive(ctx context.Context, userID int64) (*domain.TrainingPlan, error) {
+	plan, err := s.trainingRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	decodePlan(plan)
+	return plan, nil
+}
+
+func (s *TrainingService) List(ctx context.Context, userID int64, limit, offset int) ([]*domain.TrainingPlan, error) {
+	plans, err := s.trainingRepo.GetByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plan := range plans {
+		decodePlan(plan)
+	}
+	return plans, nil
+}
+
+func (s *TrainingService) Get(ctx context.Context, userID, id int64) (*domain.TrainingPlan, error) {
+	plan, err := s.trainingRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	decodePlan(plan)
+	return plan, nil
+}
+
+// Activate switches the user's active plan to an earlier one without
+// regenerating it.
+func (s *TrainingService) Activate(ctx context.Context, userID, id int64) (*domain.TrainingPlan, error) {
+	if err := s.trainingRepo.SetActive(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, userID, id)
+}
+
+func (s *TrainingService) Delete(ctx context.Context, userID, id int64) error {
+	return s.trainingRepo.Delete(ctx, id, userID)
+}
+
+// decodePlan fills plan.Plan from the stored JSON. Plans saved before the
+// schema existed are left with a nil Plan.
+func decodePlan(plan *domain.TrainingPlan) {
+	var workout domain.WorkoutPlan
+	if err := json.Unmarshal([]byte(plan.PlanJSON), &workout); err != nil || len(workout.Weeks) == 0 {
+		return
+	}
+	plan.Plan = &workout
 }
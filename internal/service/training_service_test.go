@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"gymapp/internal/domain"
+)
+
+type fakeUserRepo struct {
+	users map[int64]*domain.User
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *domain.User) error { return nil }
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user %w", domain.ErrNotFound)
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *domain.User) error { return nil }
+
+type fakeTrainingRepo struct {
+	created []*domain.TrainingPlan
+}
+
+func (r *fakeTrainingRepo) Create(ctx context.Context, plan *domain.TrainingPlan) error {
+	for _, p := range r.created {
+		if p.UserID == plan.UserID {
+			p.IsActive = false
+		}
+	}
+	plan.ID = int64(len(r.created) + 1)
+	plan.IsActive = true
+	r.created = append(r.created, plan)
+	return nil
+}
+
+func (r *fakeTrainingRepo) GetLatestByUserID(ctx context.Context, userID int64) (*domain.TrainingPlan, error) {
+	for i := len(r.created) - 1; i >= 0; i-- {
+		if r.created[i].UserID == userID {
+			return r.created[i], nil
+		}
+	}
+	return nil, fmt.Errorf("training plan %w", domain.ErrNotFound)
+}
+
+func (r *fakeTrainingRepo) GetActiveByUserID(ctx context.Context, userID int64) (*domain.TrainingPlan, error) {
+	for _, p := range r.created {
+		if p.UserID == userID && p.IsActive {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("training plan %w", domain.ErrNotFound)
+}
+
+func (r *fakeTrainingRepo) GetByID(ctx context.Context, id, userID int64) (*domain.TrainingPlan, error) {
+	for _, p := range r.created {
+		if p.ID == id && p.UserID == userID {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("training plan %w", domain.ErrNotFound)
+}
+
+func (r *fakeTrainingRepo) GetByUserID(ctx context.Context, userID int64, limit, offset int) ([]*domain.TrainingPlan, error) {
+	var plans []*domain.TrainingPlan
+	for i := len(r.created) - 1; i >= 0; i-- {
+		if r.created[i].UserID == userID {
+			plans = append(plans, r.created[i])
+		}
+	}
+	plans = plans[min(offset, len(plans)):]
+	return plans[:min(limit, len(plans))], nil
+}
+
+func (r *fakeTrainingRepo) SetActive(ctx context.Context, id, userID int64) error {
+	plan, err := r.GetByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	for _, p := range r.created {
+		if p.UserID == userID {
+			p.IsActive = p == plan
+		}
+	}
+	return nil
+}
+
+func (r *fakeTrainingRepo) Delete(ctx context.Context, id, userID int64) error { return nil }
+
+const testPlanJSON = `{"duration_weeks":4,"weeks":[{"week":1,"days":[{"day":"monday","focus":"strength","exercises":[{"name":"Squat","sets":3,"reps":5,"intensity":"high"}]}]}]}`
+
+type fakeMeasurementRepo struct {
+	measurements []*domain.BodyMeasurement
+}
+
+func (r *fakeMeasurementRepo) Create(ctx context.Context, m *domain.BodyMeasurement) error {
+	return nil
+}
+
+func (r *fakeMeasurementRepo) GetLatestWeight(ctx context.Context, userID int64) (*domain.BodyMeasurement, error) {
+	if len(r.measurements) == 0 {
+		return nil, fmt.Errorf("body measurement %w", domain.ErrNotFound)
+	}
+	return r.measurements[len(r.measurements)-1], nil
+}
+
+func (r *fakeMeasurementRepo) GetRange(ctx context.Context, userID int64, from, to int64) ([]*domain.BodyMeasurement, error) {
+	return r.measurements, nil
+}
+
+func (r *fakeMeasurementRepo) Delete(ctx context.Context, id, userID int64) error { return nil }
+
+type fakeExerciseRepo struct {
+	exercises []*domain.Exercise
+}
+
+func (r *fakeExerciseRepo) Search(ctx context.Context, filter *domain.ExerciseFilter) ([]*domain.Exercise, error) {
+	return r.exercises, nil
+}
+
+func (r *fakeExerciseRepo) GetByID(ctx context.Context, id int64) (*domain.Exercise, error) {
+	for _, ex := range r.exercises {
+		if ex.ID == id {
+			return ex, nil
+		}
+	}
+	return nil, fmt.Errorf("exercise %w", domain.ErrNotFound)
+}
+
+func (r *fakeExerciseRepo) GetAll(ctx context.Context) ([]*domain.Exercise, error) {
+	return r.exercises, nil
+}
+
+type fakeAIProvider struct {
+	planInput *domain.TrainingPlanInput
+	// planReply and planUsage, when set, replace the plan reply and the
+	// tokens it reports.
+	planReply string
+	planUsage domain.TokenUsage
+}
+
+func (p *fakeAIProvider) GenerateRecipes(ctx context.Context, input *domain.RecipeInput) (string, error) {
+	return `{"recipes":[]}`, nil
+}
+
+func (p *fakeAIProvider) GenerateTrainingPlan(ctx context.Context, input *domain.TrainingPlanInput) (string, error) {
+	p.planInput = input
+	input.Usage.Add(p.planUsage)
+	if p.planReply != "" {
+		return p.planReply, nil
+	}
+	return testPlanJSON, nil
+}
+
+func (p *fakeAIProvider) GenerateMealPlan(ctx context.Context, input *domain.MealPlanInput) (string, error) {
+	return `{"meals":[]}`, nil
+}
+
+func (p *fakeAIProvider) DetectIngredients(ctx context.Context, imageData []byte, mimeType string, usage *domain.TokenUsage) ([]string, error) {
+	return []string{"eggs"}, nil
+}
+
+func TestGeneratePlanUsesProfile(t *testing.T) {
+	users := &fakeUserRepo{users: map[int64]*domain.User{
+		1: {ID: 1, Height: 180, Weight: 90},
+	}}
+	plans := &fakeTrainingRepo{}
+	ai := &fakeAIProvider{}
+	svc := NewTrainingService(plans, users, &fakeMeasurementRepo{}, &fakeExerciseRepo{},
+		NewNutritionService(users, &fakeMeasurementRepo{}), ai, newTestUsageService(0, 0))
+
+	plan, err := svc.GeneratePlan(context.Background(), 1, &domain.GeneratePlanRequest{
+		TargetWeight:  80,
+		AvailableDays: 3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if validate := ai.planInput.Validate; validate == nil || validate(testPlanJSON) != nil || validate(`{"weeks": []}`) == nil {
+		t.Error("provider should get a check that accepts only usable plans")
+	}
+	ai.planInput.Validate = nil
+
+	want := domain.TrainingPlanInput{UserID: 1, WeightKg: 90, HeightCm: 180, TargetWeight: 80, AvailableDays: 3, Usage: &domain.TokenUsage{}}
+	if !reflect.DeepEqual(*ai.planInput, want) {
+		t.Fatalf("provider got %+v, want %+v", *ai.planInput, want)
+	}
+
+	if len(plans.created) != 1 || plan.PlanJSON != testPlanJSON {
+		t.Errorf("plan was not saved with provider output: %s", plan.PlanJSON)
+	}
+
+	if plan.Plan == nil || plan.Plan.Weeks[0].Days[0].Exercises[0].Name != "Squat" {
+		t.Errorf("structured plan not populated: %+v", plan.Plan)
+	}
+}
+
+func TestGeneratePlanRequiresProfile(t *testing.T) {
+	users := &fakeUserRepo{users: map[int64]*domain.User{1: {ID: 1}}}
+	svc := NewTrainingService(&fakeTrainingRepo{}, users, &fakeMeasurementRepo{}, &fakeExerciseRepo{},
+		NewNutritionService(users, &fakeMeasurementRepo{}), &fakeAIProvider{}, newTestUsageService(0, 0))
+
+	_, err := svc.GeneratePlan(context.Background(), 1, &domain.GeneratePlanRequest{
+		TargetWeight:  80,
+		AvailableDays: 3,
+	})
+	if err == nil {
+		t.Fatal("expected error for incomplete profile")
+	}
+}
+
+func TestGeneratePlanUsesLatestMeasurementAndTrend(t *testing.T) {
+	users := &fakeUserRepo{users: map[int64]*domain.User{
+		1: {ID: 1, Height: 180, Weight: 90},
+	}}
+	now := time.Now().Unix()
+	week := int64(7 * 24 * 3600)
+	measurements := &fakeMeasurementRepo{measurements: []*domain.BodyMeasurement{
+		{ID: 1, MeasuredAt: now - 2*week, WeightKg: 90},
+		{ID: 2, MeasuredAt: now - week, WeightKg: 89},
+		{ID: 3, MeasuredAt: now, WeightKg: 88},
+	}}
+	ai := &fakeAIProvider{}
+	svc := NewTrainingService(&fakeTrainingRepo{}, users, measurements, &fakeExerciseRepo{},
+		NewNutritionService(users, measurements), ai, newTestUsageService(0, 0))
+
+	if _, err := svc.GeneratePlan(context.Background(), 1, &domain.GeneratePlanRequest{
+		TargetWeight:  80,
+		AvailableDays: 3,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ai.planInput.WeightKg != 88 {
+		t.Errorf("expected latest measured weight 88, got %v", ai.planInput.WeightKg)
+	}
+
+	trend := ai.planInput.Trend
+	if trend == nil || trend.Samples != 3 || trend.KgPerWeek != -1 || trend.ChangeKg != -2 {
+		t.Errorf("unexpected trend: %+v", trend)
+	}
+}
+
+func TestTrainingPlanHistoryAndActivate(t *testing.T) {
+	users := &fakeUserRepo{users: map[int64]*domain.User{
+		1: {ID: 1, Height: 180, Weight: 90},
+		2: {ID: 2, Height: 170, Weight: 70},
+	}}
+	plans := &fakeTrainingRepo{}
+	svc := NewTrainingService(plans, users, &fakeMeasurementRepo{}, &fakeExerciseRepo{},
+		NewNutritionService(users, &fakeMeasurementRepo{}), &fakeAIProvider{}, newTestUsageService(0, 0))
+	ctx := context.Background()
+	req := &domain.GeneratePlanRequest{TargetWeight: 80, AvailableDays: 3}
+
+	first, err := svc.GeneratePlan(ctx, 1, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := svc.GeneratePlan(ctx, 1, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := svc.GeneratePlan(ctx, 2, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	history, err := svc.List(ctx, 1, 20, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 2 || history[0].ID != second.ID || history[1].ID != first.ID {
+		t.Fatalf("expected the user's plans newest first, got %+v", history)
+	}
+	if history[1].Plan == nil {
+		t.Error("expected history entries to carry the structured plan")
+	}
+	if page, _ := svc.List(ctx, 1, 1, 1); len(page) != 1 || page[0].ID != first.ID {
+		t.Errorf("expected the second page to hold the first plan, got %+v", page)
+	}
+
+	if active, err := svc.GetActive(ctx, 1); err != nil || active.ID != second.ID {
+		t.Fatalf("expected the newest plan to be active, got %+v, %v", active, err)
+	}
+
+	activated, err := svc.Activate(ctx, 1, first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !activated.IsActive || activated.Plan == nil {
+		t.Errorf("expected the activated plan back, got %+v", activated)
+	}
+	if active, _ := svc.GetActive(ctx, 1); active.ID != first.ID {
+		t.Errorf("expected plan %d to be active, got %d", first.ID, active.ID)
+	}
+	if !other.IsActive {
+		t.Error("activating a plan should not touch other users' plans")
+	}
+
+	if _, err := svc.Activate(ctx, 1, other.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found for another user's plan, got %v", err)
+	}
+	if _, err := svc.Get(ctx, 2, first.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found for another user's plan, got %v", err)
+	}
+}
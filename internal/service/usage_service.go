@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+	"gymapp/pkg/utils"
+)
+
+// UsageService records the tokens each user spends on AI generations and
+// enforces the daily and monthly quotas. Days and months are UTC calendar
+// periods.
+type UsageService struct {
+	usageRepo    domain.AIUsageRepository
+	dailyQuota   int64
+	monthlyQuota int64
+	logger       *utils.Logger
+	now          func() time.Time
+}
+
+func NewUsageService(usageRepo domain.AIUsageRepository, cfg *config.AIConfig, logger *utils.Logger) *UsageService {
+	return &UsageService{
+		usageRepo:    usageRepo,
+		dailyQuota:   int64(max(cfg.DailyTokenQuota, 0)),
+		monthlyQuota: int64(max(cfg.MonthlyTokenQuota, 0)),
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// CheckQuota fails once the user has used up the daily or monthly quota.
+// It runs before a generation, so the generation that crosses a quota still
+// completes; the next one is refused. Nothing is reserved, so generations
+// started in parallel may all pass the check and each overshoot the quota
+// by up to one generation's tokens.
+func (s *UsageService) CheckQuota(ctx context.Context, userID int64) error {
+	if s.dailyQuota == 0 && s.monthlyQuota == 0 {
+		return nil
+	}
+
+	summary, err := s.Summary(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, period := range []struct {
+		name  string
+		usage *domain.UsagePeriod
+	}{{"daily", summary.Day}, {"monthly", summary.Month}} {
+		if u := period.usage; u.Quota > 0 && u.TotalTokens >= u.Quota {
+			return fmt.Errorf("%w: %s AI token quota of %d used up, resets at %s", domain.ErrQuotaExceeded,
+				period.name, u.Quota, time.Unix(u.ResetsAt, 0).UTC().Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// Record saves the usage of one generation. A failure is logged rather than
+// returned: the reply has been paid for either way, and failing the request
+// would only make the client retry it.
+func (s *UsageService) Record(ctx context.Context, userID int64, endpoint string, usage domain.TokenUsage) {
+	err := s.usageRepo.Create(ctx, &domain.AIUsageRecord{
+		UserID:           userID,
+		Endpoint:         endpoint,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CreatedAt:        s.now().Unix(),
+	})
+	if err != nil {
+		s.logger.Errorf("usage: %v", err)
+	}
+}
+
+// RecordAttempt records the usage of a finished generation. A failed one is
+// only recorded when it spent tokens, which are paid for even though the
+// reply was not used. The record is saved even when ctx was cancelled
+// mid-generation.
+func (s *UsageService) RecordAttempt(ctx context.Context, userID int64, endpoint string, usage domain.TokenUsage, err error) {
+	if err != nil && usage.Total() == 0 {
+		return
+	}
+	s.Record(context.WithoutCancel(ctx), userID, endpoint, usage)
+}
+
+// Summary returns the user's usage in the current day and month with the
+// quotas that apply to them.
+func (s *UsageService) Summary(ctx context.Context, userID int64) (*domain.UsageSummary, error) {
+	now := s.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	day, err := s.period(ctx, userID, dayStart, dayStart.AddDate(0, 0, 1), s.dailyQuota)
+	if err != nil {
+		return nil, err
+	}
+
+	month, err := s.period(ctx, userID, monthStart, monthStart.AddDate(0, 1, 0), s.monthlyQuota)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UsageSummary{Day: day, Month: month}, nil
+}
+
+func (s *UsageService) period(ctx context.Context, userID int64, start, end time.Time, quota int64) (*domain.UsagePeriod, error) {
+	endpoints, err := s.usageRepo.Summarize(ctx, userID, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	period := &domain.UsagePeriod{
+		Start:     start.Unix(),
+		ResetsAt:  end.Unix(),
+		Quota:     quota,
+		Endpoints: endpoints,
+	}
+	for _, e := range endpoints {
+		period.Requests += e.Requests
+		period.PromptTokens += e.PromptTokens
+		period.CompletionTokens += e.CompletionTokens
+		period.TotalTokens += e.TotalTokens
+	}
+	if period.Endpoints == nil {
+		period.Endpoints = []*domain.EndpointUsage{}
+	}
+
+	if quota > 0 {
+		remaining := max(quota-period.TotalTokens, 0)
+		period.Remaining = &remaining
+	}
+
+	return period, nil
+}
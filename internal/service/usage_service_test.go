@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gymapp/internal/config"
+	"gymapp/internal/domain"
+)
+
+var usageTestNow = time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+type fakeAIUsageRepo struct {
+	records []*domain.AIUsageRecord
+}
+
+func (r *fakeAIUsageRepo) Create(ctx context.Context, record *domain.AIUsageRecord) error {
+	record.ID = int64(len(r.records) + 1)
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (r *fakeAIUsageRepo) Summarize(ctx context.Context, userID int64, from, to int64) ([]*domain.EndpointUsage, error) {
+	byEndpoint := make(map[string]*domain.EndpointUsage)
+	var usage []*domain.EndpointUsage
+	for _, rec := range r.records {
+		if rec.UserID != userID || rec.CreatedAt < from || rec.CreatedAt >= to {
+			continue
+		}
+		u, ok := byEndpoint[rec.Endpoint]
+		if !ok {
+			u = &domain.EndpointUsage{Endpoint: rec.Endpoint}
+			byEndpoint[rec.Endpoint] = u
+			usage = append(usage, u)
+		}
+		u.Requests++
+		u.PromptTokens += rec.PromptTokens
+		u.CompletionTokens += rec.CompletionTokens
+		u.TotalTokens += rec.PromptTokens + rec.CompletionTokens
+	}
+	return usage, nil
+}
+
+func newTestUsageService(daily, monthly int) *UsageService {
+	svc := NewUsageService(&fakeAIUsageRepo{}, &config.AIConfig{DailyTokenQuota: daily, MonthlyTokenQuota: monthly}, nil)
+	svc.now = func() time.Time { return usageTestNow }
+	return svc
+}
+
+func TestUsageServiceQuotas(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestUsageService(100, 250)
+	repo := svc.usageRepo.(*fakeAIUsageRepo)
+
+	repo.Create(ctx, &domain.AIUsageRecord{UserID: 1, Endpoint: domain.UsageMealPlan,
+		PromptTokens: 120, CompletionTokens: 60, CreatedAt: usageTestNow.AddDate(0, 0, -1).Unix()})
+	repo.Create(ctx, &domain.AIUsageRecord{UserID: 1, Endpoint: domain.UsageMealPlan,
+		PromptTokens: 500, CreatedAt: usageTestNow.AddDate(0, -1, 0).Unix()})
+	svc.Record(ctx, 1, domain.UsageRecipeText, domain.TokenUsage{PromptTokens: 40, CompletionTokens: 20})
+
+	if err := svc.CheckQuota(ctx, 1); err != nil {
+		t.Fatalf("expected usage within quotas, got %v", err)
+	}
+
+	summary, err := svc.Summary(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if day := summary.Day; day.TotalTokens != 60 || day.Requests != 1 || *day.Remaining != 40 ||
+		day.ResetsAt != time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("unexpected day usage: %+v", day)
+	}
+	if month := summary.Month; month.TotalTokens != 240 || len(month.Endpoints) != 2 || *month.Remaining != 10 {
+		t.Errorf("unexpected month usage: %+v", month)
+	}
+
+	svc.Record(ctx, 1, domain.UsageRecipeText, domain.TokenUsage{PromptTokens: 10})
+	if err := svc.CheckQuota(ctx, 1); !errors.Is(err, domain.ErrQuotaExceeded) || !strings.Contains(err.Error(), "monthly") {
+		t.Errorf("expected the monthly quota to be used up, got %v", err)
+	}
+
+	svc.Record(ctx, 1, domain.UsageRecipeText, domain.TokenUsage{CompletionTokens: 30})
+	if err := svc.CheckQuota(ctx, 1); !errors.Is(err, domain.ErrQuotaExceeded) || !strings.Contains(err.Error(), "daily") {
+		t.Errorf("expected the daily quota to be used up, got %v", err)
+	}
+
+	if err := svc.CheckQuota(ctx, 2); err != nil {
+		t.Errorf("other users should not share the quota, got %v", err)
+	}
+}
+
+func TestGeneratePlanChecksQuotaAndRecordsUsage(t *testing.T) {
+	users := &fakeUserRepo{users: map[int64]*domain.User{
+		1: {ID: 1, Height: 180, Weight: 90},
+	}}
+	usage := newTestUsageService(50, 0)
+	ai := &fakeAIProvider{}
+	svc := NewTrainingService(&fakeTrainingRepo{}, users, &fakeMeasurementRepo{}, &fakeExerciseRepo{},
+		NewNutritionService(users, &fakeMeasurementRepo{}), ai, usage)
+	req := &domain.GeneratePlanRequest{TargetWeight: 80, AvailableDays: 3}
+
+	if _, err := svc.GeneratePlan(context.Background(), 1, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo := usage.usageRepo.(*fakeAIUsageRepo)
+	if len(repo.records) != 1 || repo.records[0].Endpoint != domain.UsageTrainingPlan {
+		t.Fatalf("expected one training plan usage record, got %+v", repo.records)
+	}
+
+	repo.Create(context.Background(), &domain.AIUsageRecord{UserID: 1, Endpoint: domain.UsageRecipeText,
+		PromptTokens: 50, CreatedAt: usageTestNow.Unix()})
+	ai.planInput = nil
+
+	if _, err := svc.GeneratePlan(context.Background(), 1, req); !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	if ai.planInput != nil {
+		t.Error("provider should not be called once the quota is used up")
+	}
+}
+
+func TestFailedGenerationRecordsSpentTokens(t *testing.T) {
+	users := &fakeUserRepo{users: map[int64]*domain.User{
+		1: {ID: 1, Height: 180, Weight: 90},
+	}}
+	usage := newTestUsageService(0, 0)
+	ai := &fakeAIProvider{planReply: "not a plan"}
+	svc := NewTrainingService(&fakeTrainingRepo{}, users, &fakeMeasurementRepo{}, &fakeExerciseRepo{},
+		NewNutritionService(users, &fakeMeasurementRepo{}), ai, usage)
+	req := &domain.GeneratePlanRequest{TargetWeight: 80, AvailableDays: 3}
+	repo := usage.usageRepo.(*fakeAIUsageRepo)
+
+	if _, err := svc.GeneratePlan(context.Background(), 1, req); err == nil {
+		t.Fatal("expected an unparseable plan to fail")
+	}
+	if len(repo.records) != 0 {
+		t.Fatalf("a failure that spent nothing should not be recorded, got %+v", repo.records)
+	}
+
+	ai.planUsage = domain.TokenUsage{PromptTokens: 30, CompletionTokens: 70}
+	if _, err := svc.GeneratePlan(context.Background(), 1, req); err == nil {
+		t.Fatal("expected an unparseable plan to fail")
+	}
+	if len(repo.records) != 1 || repo.records[0].PromptTokens != 30 || repo.records[0].CompletionTokens != 70 {
+		t.Errorf("expected the spent tokens to be recorded, got %+v", repo.records)
+	}
+}
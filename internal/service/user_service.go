@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gymapp/internal/domain"
+)
+
+const (
+	minHeight = 50
+	maxHeight = 300
+	minWeight = 20
+	maxWeight = 500
+	minAge    = 13
+	maxAge    = 120
+)
+
+type UserService struct {
+	userRepo domain.UserRepository
+}
+
+func NewUserService(userRepo domain.UserRepository) *UserService {
+	return &UserService{userRepo: userRepo}
+}
+
+func (s *UserService) GetProfile(ctx context.Context, userID int64) (*domain.User, error) {
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+// ReplaceProfile requires height, weight and goal. Sex, birth year,
+// activity level and dietary preferences are optional and keep their
+// current values when omitted.
+func (s *UserService) ReplaceProfile(ctx context.Context, userID int64, req *domain.UpdateProfileRequest) (*domain.User, error) {
+	if req.Height == nil || req.Weight == nil || req.Goal == nil {
+		return nil, fmt.Errorf("%w: height, weight and goal are required", domain.ErrInvalidInput)
+	}
+
+	return s.update(ctx, userID, req)
+}
+
+func (s *UserService) PatchProfile(ctx context.Context, userID int64, req *domain.UpdateProfileRequest) (*domain.User, error) {
+	if req.Height == nil && req.Weight == nil && req.Goal == nil &&
+		req.Sex == nil && req.BirthYear == nil && req.ActivityLevel == nil &&
+		req.Restrictions == nil && req.Allergens == nil && req.Dislikes == nil {
+		return nil, fmt.Errorf("%w: at least one profile field is required", domain.ErrInvalidInput)
+	}
+
+	return s.update(ctx, userID, req)
+}
+
+func (s *UserService) update(ctx context.Context, userID int64, req *domain.UpdateProfileRequest) (*domain.User, error) {
+	if err := validateProfile(req); err != nil {
+		return nil, err
+	}
+
+	diet, err := normalizeDiet(req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Height != nil {
+		user.Height = *req.Height
+	}
+	if req.Weight != nil {
+		user.Weight = *req.Weight
+	}
+	if req.Goal != nil {
+		user.Goal = *req.Goal
+	}
+	if req.Sex != nil {
+		user.Sex = *req.Sex
+	}
+	if req.BirthYear != nil {
+		user.BirthYear = *req.BirthYear
+	}
+	if req.ActivityLevel != nil {
+		user.ActivityLevel = *req.ActivityLevel
+	}
+	if req.Restrictions != nil {
+		user.Diet.Restrictions = diet.Restrictions
+	}
+	if req.Allergens != nil {
+		user.Diet.Allergens = diet.Allergens
+	}
+	if req.Dislikes != nil {
+		user.Diet.Dislikes = diet.Dislikes
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return user, nil
+}
+
+func validateProfile(req *domain.UpdateProfileRequest) error {
+	if req.Height != nil && (*req.Height < minHeight || *req.Height > maxHeight) {
+		return fmt.Errorf("%w: height must be between %d and %d cm", domain.ErrInvalidInput, minHeight, maxHeight)
+	}
+
+	if req.Weight != nil && (*req.Weight < minWeight || *req.Weight > maxWeight) {
+		return fmt.Errorf("%w: weight must be between %d and %d kg", domain.ErrInvalidInput, minWeight, maxWeight)
+	}
+
+	if req.Goal != nil {
+		switch *req.Goal {
+		case domain.GoalCut, domain.GoalMaintain, domain.GoalBulk:
+		default:
+			return fmt.Errorf("%w: goal must be one of %s, %s, %s", domain.ErrInvalidInput, domain.GoalCut, domain.GoalMaintain, domain.GoalBulk)
+		}
+	}
+
+	if req.Sex != nil {
+		switch *req.Sex {
+		case domain.SexMale, domain.SexFemale:
+		default:
+			return fmt.Errorf("%w: sex must be %s or %s", domain.ErrInvalidInput, domain.SexMale, domain.SexFemale)
+		}
+	}
+
+	if req.BirthYear != nil {
+		year := time.Now().Year()
+		if *req.BirthYear < year-maxAge || *req.BirthYear > year-minAge {
+			return fmt.Errorf("%w: birth_year must be between %d and %d", domain.ErrInvalidInput, year-maxAge, year-minAge)
+		}
+	}
+
+	if req.ActivityLevel != nil {
+		if _, ok := activityFactors[*req.ActivityLevel]; !ok {
+			return fmt.Errorf("%w: activity_level must be one of sedentary, light, moderate, active, very_active", domain.ErrInvalidInput)
+		}
+	}
+
+	return nil
+}
+
+// normalizeDiet cleans up the dietary lists present in req. Restrictions
+// must be ones recipe validation understands; allergens and dislikes are
+// free-form.
+func normalizeDiet(req *domain.UpdateProfileRequest) (domain.DietaryPreferences, error) {
+	var diet domain.DietaryPreferences
+	var err error
+
+	if req.Restrictions != nil {
+		if diet.Restrictions, err = normalizeDietList("dietary_restrictions", *req.Restrictions); err != nil {
+			return diet, err
+		}
+		for i, r := range diet.Restrictions {
+			r = strings.NewReplacer(" ", "_", "-", "_").Replace(r)
+			if _, ok := restrictionGroups[r]; !ok {
+				return diet, fmt.Errorf("%w: unknown dietary restriction %q", domain.ErrInvalidInput, r)
+			}
+			diet.Restrictions[i] = r
+		}
+	}
+
+	if req.Allergens != nil {
+		if diet.Allergens, err = normalizeDietList("allergens", *req.Allergens); err != nil {
+			return diet, err
+		}
+	}
+
+	if req.Dislikes != nil {
+		if diet.Dislikes, err = normalizeDietList("disliked_foods", *req.Dislikes); err != nil {
+			return diet, err
+		}
+	}
+
+	return diet, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gymapp/internal/domain"
+)
+
+func TestUpdateProfileErrors(t *testing.T) {
+	svc := NewUserService(&fakeUserRepo{users: map[int64]*domain.User{
+		1: {ID: 1, Height: 180, Weight: 90, Goal: domain.GoalCut},
+	}})
+	ctx := context.Background()
+	height, weight, goal := 180, 85, domain.GoalMaintain
+	tooTall := 400
+
+	if _, err := svc.ReplaceProfile(ctx, 1, &domain.UpdateProfileRequest{Height: &height}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for a partial replace, got %v", err)
+	}
+	if _, err := svc.PatchProfile(ctx, 1, &domain.UpdateProfileRequest{}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for an empty patch, got %v", err)
+	}
+	if _, err := svc.PatchProfile(ctx, 1, &domain.UpdateProfileRequest{Height: &tooTall}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for an out of range height, got %v", err)
+	}
+	if _, err := svc.PatchProfile(ctx, 1, &domain.UpdateProfileRequest{Restrictions: &[]string{"paleo"}}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("expected invalid input for an unknown restriction, got %v", err)
+	}
+	if _, err := svc.PatchProfile(ctx, 2, &domain.UpdateProfileRequest{Weight: &weight}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected not found for an unknown user, got %v", err)
+	}
+
+	user, err := svc.ReplaceProfile(ctx, 1, &domain.UpdateProfileRequest{Height: &height, Weight: &weight, Goal: &goal})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Weight != 85 || user.Goal != domain.GoalMaintain {
+		t.Errorf("profile was not updated: %+v", user)
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gymapp/internal/domain"
+)
+
+const (
+	maxSetReps        = 1000
+	maxSetWeightKg    = 1000
+	maxSetRestSeconds = 3600
+	maxRPE            = 10
+)
+
+type WorkoutService struct {
+	workoutRepo  domain.WorkoutRepository
+	exerciseRepo domain.ExerciseRepository
+}
+
+func NewWorkoutService(workoutRepo domain.WorkoutRepository, exerciseRepo domain.ExerciseRepository) *WorkoutService {
+	return &WorkoutService{
+		workoutRepo:  workoutRepo,
+		exerciseRepo: exerciseRepo,
+	}
+}
+
+func (s *WorkoutService) Create(ctx context.Context, userID int64, session *domain.WorkoutSession) (*domain.WorkoutSession, error) {
+	if err := s.linkExercises(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if err := normalizeWorkout(session); err != nil {
+		return nil, err
+	}
+
+	session.UserID = userID
+	if err := s.workoutRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save workout: %w", err)
+	}
+
+	return session, nil
+}
+
+func (s *WorkoutService) Get(ctx context.Context, userID, id int64) (*domain.WorkoutSession, error) {
+	return s.workoutRepo.GetByID(ctx, id, userID)
+}
+
+func (s *WorkoutService) List(ctx context.Context, userID int64, limit, offset int) ([]*domain.WorkoutSession, error) {
+	return s.workoutRepo.GetByUserID(ctx, userID, limit, offset)
+}
+
+func (s *WorkoutService) Update(ctx context.Context, userID, id int64, session *domain.WorkoutSession) (*domain.WorkoutSession, error) {
+	if err := s.linkExercises(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if err := normalizeWorkout(session); err != nil {
+		return nil, err
+	}
+
+	session.ID = id
+	session.UserID = userID
+	if err := s.workoutRepo.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update workout: %w", err)
+	}
+
+	return session, nil
+}
+
+func (s *WorkoutService) Delete(ctx context.Context, userID, id int64) error {
+	return s.workoutRepo.Delete(ctx, id, userID)
+}
+
+// linkExercises checks catalog references and names unnamed exercises after
+// their catalog entry.
+func (s *WorkoutService) linkExercises(ctx context.Context, session *domain.WorkoutSession) error {
+	for i := range session.Exercises {
+		ex := &session.Exercises[i]
+		if ex.ExerciseID == 0 {
+			continue
+		}
+
+		entry, err := s.exerciseRepo.GetByID(ctx, ex.ExerciseID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("%w: exercise %d: unknown exercise_id %d", domain.ErrInvalidInput, i+1, ex.ExerciseID)
+			}
+			return err
+		}
+
+		if strings.TrimSpace(ex.Name) == "" {
+			ex.Name = entry.Name
+		}
+	}
+
+	return nil
+}
+
+// normalizeWorkout trims names, defaults the start time to now and checks
+// every set against sane bounds.
+func normalizeWorkout(session *domain.WorkoutSession) error {
+	session.Name = strings.TrimSpace(session.Name)
+	if session.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidInput)
+	}
+
+	if session.StartedAt == 0 {
+		session.StartedAt = time.Now().Unix()
+	}
+
+	if session.EndedAt != 0 && session.EndedAt < session.StartedAt {
+		return fmt.Errorf("%w: ended_at must not be before started_at", domain.ErrInvalidInput)
+	}
+
+	if session.Exercises == nil {
+		session.Exercises = []domain.WorkoutExercise{}
+	}
+
+	for i := range session.Exercises {
+		ex := &session.Exercises[i]
+		ex.Name = strings.TrimSpace(ex.Name)
+		if ex.Name == "" {
+			return fmt.Errorf("%w: exercise %d: name is required", domain.ErrInvalidInput, i+1)
+		}
+
+		if ex.Sets == nil {
+			ex.Sets = []domain.WorkoutSet{}
+		}
+
+		for j, set := range ex.Sets {
+			if err := validateSet(set); err != nil {
+				return fmt.Errorf("%w: exercise %d set %d: %v", domain.ErrInvalidInput, i+1, j+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateSet(set domain.WorkoutSet) error {
+	if set.Reps < 0 || set.Reps > maxSetReps {
+		return fmt.Errorf("reps must be between 0 and %d", maxSetReps)
+	}
+
+	if set.WeightKg < 0 || set.WeightKg > maxSetWeightKg {
+		return fmt.Errorf("weight_kg must be between 0 and %d", maxSetWeightKg)
+	}
+
+	if set.RPE != 0 && (set.RPE < 1 || set.RPE > maxRPE) {
+		return fmt.Errorf("rpe must be between 1 and %d", maxRPE)
+	}
+
+	if set.RestSeconds < 0 || set.RestSeconds > maxSetRestSeconds {
+		return fmt.Errorf("rest_seconds must be between 0 and %d", maxSetRestSeconds)
+	}
+
+	return nil
+}
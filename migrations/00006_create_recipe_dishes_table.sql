@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE recipe_dishes (
+    id BIGSERIAL PRIMARY KEY,
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    servings INT NOT NULL,
+    prep_time_minutes INT NOT NULL,
+    calories INT NOT NULL,
+    protein_g NUMERIC(7, 2) NOT NULL,
+    carbs_g NUMERIC(7, 2) NOT NULL,
+    fat_g NUMERIC(7, 2) NOT NULL,
+    steps TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_recipe_dishes_recipe_id ON recipe_dishes(recipe_id);
+
+CREATE TABLE recipe_dish_ingredients (
+    id BIGSERIAL PRIMARY KEY,
+    dish_id BIGINT NOT NULL REFERENCES recipe_dishes(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity NUMERIC(10, 2) NOT NULL,
+    unit VARCHAR(50) NOT NULL
+);
+
+CREATE INDEX idx_recipe_dish_ingredients_dish_id ON recipe_dish_ingredients(dish_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS recipe_dish_ingredients;
+DROP TABLE IF EXISTS recipe_dishes;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workout_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    started_at BIGINT NOT NULL,
+    ended_at BIGINT,
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX idx_workout_sessions_user_id_started_at ON workout_sessions(user_id, started_at DESC);
+
+CREATE TABLE workout_exercises (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_workout_exercises_session_id ON workout_exercises(session_id);
+
+CREATE TABLE workout_sets (
+    id BIGSERIAL PRIMARY KEY,
+    exercise_id BIGINT NOT NULL REFERENCES workout_exercises(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    reps INT NOT NULL,
+    weight_kg NUMERIC(6, 2) NOT NULL,
+    rpe NUMERIC(3, 1),
+    rest_seconds INT
+);
+
+CREATE INDEX idx_workout_sets_exercise_id ON workout_sets(exercise_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS workout_sets;
+DROP TABLE IF EXISTS workout_exercises;
+DROP TABLE IF EXISTS workout_sessions;
+-- +goose StatementEnd
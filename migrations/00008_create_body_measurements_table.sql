@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE body_measurements (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    measured_at BIGINT NOT NULL,
+    weight_kg NUMERIC(5, 2),
+    body_fat_pct NUMERIC(4, 1),
+    waist_cm NUMERIC(5, 1),
+    chest_cm NUMERIC(5, 1),
+    hips_cm NUMERIC(5, 1),
+    arm_cm NUMERIC(5, 1),
+    thigh_cm NUMERIC(5, 1),
+    notes TEXT NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX idx_body_measurements_user_id_measured_at ON body_measurements(user_id, measured_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS body_measurements;
+-- +goose StatementEnd
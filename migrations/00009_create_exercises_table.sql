@@ -0,0 +1,101 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE exercises (
+    id BIGSERIAL PRIMARY KEY,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    primary_muscles TEXT[] NOT NULL DEFAULT '{}',
+    secondary_muscles TEXT[] NOT NULL DEFAULT '{}',
+    equipment VARCHAR(50) NOT NULL,
+    movement_pattern VARCHAR(50) NOT NULL,
+    difficulty VARCHAR(20) NOT NULL,
+    instructions TEXT NOT NULL
+);
+
+CREATE INDEX idx_exercises_primary_muscles ON exercises USING GIN (primary_muscles);
+CREATE INDEX idx_exercises_equipment ON exercises(equipment);
+CREATE INDEX idx_exercises_movement_pattern ON exercises(movement_pattern);
+
+INSERT INTO exercises (slug, name, primary_muscles, secondary_muscles, equipment, movement_pattern, difficulty, instructions) VALUES
+    ('barbell-squat', 'Barbell Squat', ARRAY['quadriceps', 'glutes']::TEXT[], ARRAY['hamstrings', 'core']::TEXT[], 'barbell', 'squat', 'intermediate',
+     'Set the bar on your upper back, brace, sit down between your hips until thighs are parallel, then drive up through mid-foot.'),
+    ('goblet-squat', 'Goblet Squat', ARRAY['quadriceps', 'glutes']::TEXT[], ARRAY['core']::TEXT[], 'dumbbell', 'squat', 'beginner',
+     'Hold a dumbbell at your chest, squat down keeping the torso upright, and stand back up.'),
+    ('leg-press', 'Leg Press', ARRAY['quadriceps', 'glutes']::TEXT[], ARRAY['hamstrings']::TEXT[], 'machine', 'squat', 'beginner',
+     'Place feet shoulder-width on the platform, lower until knees reach 90 degrees, and press back without locking the knees.'),
+    ('bodyweight-squat', 'Bodyweight Squat', ARRAY['quadriceps', 'glutes']::TEXT[], '{}'::TEXT[], 'bodyweight', 'squat', 'beginner',
+     'Stand with feet shoulder-width apart, sit back and down, then stand up.'),
+    ('deadlift', 'Deadlift', ARRAY['hamstrings', 'glutes', 'lower back']::TEXT[], ARRAY['forearms', 'core']::TEXT[], 'barbell', 'hinge', 'advanced',
+     'With the bar over mid-foot, hinge to grip it, brace, and stand up by driving the floor away while keeping the bar close.'),
+    ('romanian-deadlift', 'Romanian Deadlift', ARRAY['hamstrings', 'glutes']::TEXT[], ARRAY['lower back']::TEXT[], 'barbell', 'hinge', 'intermediate',
+     'Holding the bar at hip height, push the hips back with soft knees until you feel a hamstring stretch, then return.'),
+    ('kettlebell-swing', 'Kettlebell Swing', ARRAY['glutes', 'hamstrings']::TEXT[], ARRAY['core', 'shoulders']::TEXT[], 'kettlebell', 'hinge', 'intermediate',
+     'Hike the bell between your legs and snap the hips forward to swing it to chest height.'),
+    ('hip-thrust', 'Hip Thrust', ARRAY['glutes']::TEXT[], ARRAY['hamstrings']::TEXT[], 'barbell', 'hinge', 'intermediate',
+     'With upper back on a bench and the bar over your hips, drive the hips up to full extension and lower under control.'),
+    ('walking-lunge', 'Walking Lunge', ARRAY['quadriceps', 'glutes']::TEXT[], ARRAY['hamstrings', 'core']::TEXT[], 'bodyweight', 'lunge', 'beginner',
+     'Step forward and lower the back knee toward the floor, then step through into the next rep.'),
+    ('bulgarian-split-squat', 'Bulgarian Split Squat', ARRAY['quadriceps', 'glutes']::TEXT[], ARRAY['core']::TEXT[], 'dumbbell', 'lunge', 'intermediate',
+     'With the rear foot on a bench, lower the back knee toward the floor and drive up through the front foot.'),
+    ('bench-press', 'Bench Press', ARRAY['chest']::TEXT[], ARRAY['triceps', 'shoulders']::TEXT[], 'barbell', 'horizontal_push', 'intermediate',
+     'Lower the bar to mid-chest with elbows about 45 degrees from the torso, then press to lockout.'),
+    ('dumbbell-bench-press', 'Dumbbell Bench Press', ARRAY['chest']::TEXT[], ARRAY['triceps', 'shoulders']::TEXT[], 'dumbbell', 'horizontal_push', 'beginner',
+     'Press dumbbells from chest level to lockout, lowering them under control.'),
+    ('push-up', 'Push-Up', ARRAY['chest']::TEXT[], ARRAY['triceps', 'shoulders', 'core']::TEXT[], 'bodyweight', 'horizontal_push', 'beginner',
+     'From a plank, lower your chest to the floor keeping the body straight, then push back up.'),
+    ('overhead-press', 'Overhead Press', ARRAY['shoulders']::TEXT[], ARRAY['triceps', 'core']::TEXT[], 'barbell', 'vertical_push', 'intermediate',
+     'Press the bar from the front of the shoulders to overhead lockout, keeping glutes and core tight.'),
+    ('dumbbell-shoulder-press', 'Dumbbell Shoulder Press', ARRAY['shoulders']::TEXT[], ARRAY['triceps']::TEXT[], 'dumbbell', 'vertical_push', 'beginner',
+     'Press dumbbells from shoulder height to overhead and lower under control.'),
+    ('dip', 'Dip', ARRAY['chest', 'triceps']::TEXT[], ARRAY['shoulders']::TEXT[], 'bodyweight', 'vertical_push', 'intermediate',
+     'Lower yourself between parallel bars until the shoulders are just below the elbows, then press up.'),
+    ('bent-over-row', 'Bent-Over Row', ARRAY['upper back', 'lats']::TEXT[], ARRAY['biceps', 'lower back']::TEXT[], 'barbell', 'horizontal_pull', 'intermediate',
+     'Hinge forward with a flat back and row the bar to your lower ribs.'),
+    ('dumbbell-row', 'Dumbbell Row', ARRAY['lats', 'upper back']::TEXT[], ARRAY['biceps']::TEXT[], 'dumbbell', 'horizontal_pull', 'beginner',
+     'With one hand on a bench, row the dumbbell toward your hip and lower it under control.'),
+    ('seated-cable-row', 'Seated Cable Row', ARRAY['upper back', 'lats']::TEXT[], ARRAY['biceps']::TEXT[], 'cable', 'horizontal_pull', 'beginner',
+     'Sit tall and pull the handle to your abdomen, squeezing the shoulder blades together.'),
+    ('pull-up', 'Pull-Up', ARRAY['lats']::TEXT[], ARRAY['biceps', 'upper back']::TEXT[], 'bodyweight', 'vertical_pull', 'advanced',
+     'Hang from the bar with an overhand grip and pull until your chin clears the bar.'),
+    ('lat-pulldown', 'Lat Pulldown', ARRAY['lats']::TEXT[], ARRAY['biceps', 'upper back']::TEXT[], 'cable', 'vertical_pull', 'beginner',
+     'Pull the bar to your upper chest while keeping the torso still, then return slowly.'),
+    ('face-pull', 'Face Pull', ARRAY['rear delts', 'upper back']::TEXT[], ARRAY['rotator cuff']::TEXT[], 'cable', 'horizontal_pull', 'beginner',
+     'Pull the rope toward your face, separating the hands and finishing with elbows high.'),
+    ('biceps-curl', 'Biceps Curl', ARRAY['biceps']::TEXT[], ARRAY['forearms']::TEXT[], 'dumbbell', 'isolation', 'beginner',
+     'Curl the dumbbells to shoulder height without swinging and lower under control.'),
+    ('triceps-pushdown', 'Triceps Pushdown', ARRAY['triceps']::TEXT[], '{}'::TEXT[], 'cable', 'isolation', 'beginner',
+     'Keeping elbows pinned to your sides, push the bar down to full extension.'),
+    ('lateral-raise', 'Lateral Raise', ARRAY['shoulders']::TEXT[], '{}'::TEXT[], 'dumbbell', 'isolation', 'beginner',
+     'Raise the dumbbells out to the sides to shoulder height with a slight bend in the elbows.'),
+    ('calf-raise', 'Calf Raise', ARRAY['calves']::TEXT[], '{}'::TEXT[], 'machine', 'isolation', 'beginner',
+     'Rise onto the balls of your feet, pause at the top, and lower through the full range.'),
+    ('farmers-carry', 'Farmer''s Carry', ARRAY['forearms', 'core']::TEXT[], ARRAY['traps', 'shoulders']::TEXT[], 'dumbbell', 'carry', 'beginner',
+     'Hold heavy dumbbells at your sides and walk with an upright posture.'),
+    ('plank', 'Plank', ARRAY['core']::TEXT[], ARRAY['shoulders']::TEXT[], 'bodyweight', 'core', 'beginner',
+     'Hold a straight line from head to heels on forearms and toes, bracing the abs.'),
+    ('hanging-leg-raise', 'Hanging Leg Raise', ARRAY['core']::TEXT[], ARRAY['hip flexors']::TEXT[], 'bodyweight', 'core', 'advanced',
+     'Hang from a bar and raise the legs to hip height or higher without swinging.'),
+    ('dead-bug', 'Dead Bug', ARRAY['core']::TEXT[], '{}'::TEXT[], 'bodyweight', 'core', 'beginner',
+     'Lying on your back, extend opposite arm and leg while keeping the lower back pressed to the floor.'),
+    ('stationary-bike', 'Stationary Bike', ARRAY['quadriceps']::TEXT[], ARRAY['calves', 'cardiovascular']::TEXT[], 'cardio_machine', 'cardio', 'beginner',
+     'Pedal at a steady cadence at the prescribed intensity.'),
+    ('treadmill-run', 'Treadmill Run', ARRAY['cardiovascular']::TEXT[], ARRAY['quadriceps', 'calves']::TEXT[], 'cardio_machine', 'cardio', 'beginner',
+     'Run at a steady pace at the prescribed intensity.'),
+    ('rowing-machine', 'Rowing Machine', ARRAY['cardiovascular', 'upper back']::TEXT[], ARRAY['quadriceps', 'core']::TEXT[], 'cardio_machine', 'cardio', 'beginner',
+     'Drive with the legs, then lean back and pull the handle to your ribs; reverse the order on the return.'),
+    ('brisk-walk', 'Brisk Walk', ARRAY['cardiovascular']::TEXT[], ARRAY['calves']::TEXT[], 'none', 'cardio', 'beginner',
+     'Walk at a pace where you can talk but not sing.'),
+    ('jump-rope', 'Jump Rope', ARRAY['cardiovascular', 'calves']::TEXT[], ARRAY['shoulders']::TEXT[], 'none', 'cardio', 'intermediate',
+     'Jump with small hops, turning the rope with the wrists.'),
+    ('burpee', 'Burpee', ARRAY['cardiovascular']::TEXT[], ARRAY['chest', 'quadriceps', 'core']::TEXT[], 'bodyweight', 'cardio', 'intermediate',
+     'Squat, kick back to a plank, return the feet, and jump up.');
+
+ALTER TABLE workout_exercises
+    ADD COLUMN exercise_id BIGINT REFERENCES exercises(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE workout_exercises DROP COLUMN IF EXISTS exercise_id;
+DROP TABLE IF EXISTS exercises;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE training_plans ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE training_plans SET is_active = TRUE
+WHERE id IN (
+    SELECT DISTINCT ON (user_id) id
+    FROM training_plans
+    ORDER BY user_id, created_at DESC, id DESC
+);
+
+CREATE UNIQUE INDEX idx_training_plans_active ON training_plans(user_id) WHERE is_active;
+CREATE INDEX idx_training_plans_user_created ON training_plans(user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_training_plans_user_created;
+DROP INDEX IF EXISTS idx_training_plans_active;
+ALTER TABLE training_plans DROP COLUMN IF EXISTS is_active;
+-- +goose StatementEnd
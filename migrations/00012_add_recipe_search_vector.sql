@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE recipes ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(ingredients, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(ai_response, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_recipes_search_vector ON recipes USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_recipes_search_vector;
+ALTER TABLE recipes DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
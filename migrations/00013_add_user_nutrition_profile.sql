@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN sex VARCHAR(10) NOT NULL DEFAULT '',
+    ADD COLUMN birth_year INT NOT NULL DEFAULT 0,
+    ADD COLUMN activity_level VARCHAR(20) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS activity_level,
+    DROP COLUMN IF EXISTS birth_year,
+    DROP COLUMN IF EXISTS sex;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE food_diary_entries (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    eaten_at BIGINT NOT NULL,
+    meal VARCHAR(20) NOT NULL,
+    recipe_id BIGINT REFERENCES recipes(id) ON DELETE SET NULL,
+    dish_id BIGINT REFERENCES recipe_dishes(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    servings NUMERIC(6, 2) NOT NULL DEFAULT 1,
+    calories INT NOT NULL,
+    protein_g NUMERIC(7, 2) NOT NULL DEFAULT 0,
+    carbs_g NUMERIC(7, 2) NOT NULL DEFAULT 0,
+    fat_g NUMERIC(7, 2) NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX idx_food_diary_entries_user_id_eaten_at ON food_diary_entries(user_id, eaten_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS food_diary_entries;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE meal_plans (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    calorie_target INT NOT NULL,
+    preferences TEXT NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX idx_meal_plans_user_id ON meal_plans(user_id);
+
+CREATE TABLE meal_plan_slots (
+    id BIGSERIAL PRIMARY KEY,
+    plan_id BIGINT NOT NULL REFERENCES meal_plans(id) ON DELETE CASCADE,
+    day VARCHAR(10) NOT NULL,
+    meal VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    servings INT NOT NULL,
+    prep_time_minutes INT NOT NULL,
+    calories INT NOT NULL,
+    protein_g NUMERIC(7, 2) NOT NULL,
+    carbs_g NUMERIC(7, 2) NOT NULL,
+    fat_g NUMERIC(7, 2) NOT NULL,
+    ingredients JSONB NOT NULL DEFAULT '[]',
+    steps TEXT[] NOT NULL DEFAULT '{}',
+    updated_at BIGINT NOT NULL,
+    UNIQUE (plan_id, day, meal)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS meal_plan_slots;
+DROP TABLE IF EXISTS meal_plans;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE shopping_lists (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX idx_shopping_lists_user_id ON shopping_lists(user_id);
+
+CREATE TABLE shopping_list_items (
+    id BIGSERIAL PRIMARY KEY,
+    list_id BIGINT NOT NULL REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity NUMERIC(10, 2) NOT NULL,
+    unit VARCHAR(50) NOT NULL,
+    checked BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_shopping_list_items_list_id ON shopping_list_items(list_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shopping_list_items;
+DROP TABLE IF EXISTS shopping_lists;
+-- +goose StatementEnd
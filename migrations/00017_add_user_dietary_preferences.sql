@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN dietary_restrictions TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN allergens TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN disliked_foods TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE recipe_dishes ADD COLUMN flags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE meal_plan_slots ADD COLUMN flags TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE meal_plan_slots DROP COLUMN IF EXISTS flags;
+ALTER TABLE recipe_dishes DROP COLUMN IF EXISTS flags;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS disliked_foods,
+    DROP COLUMN IF EXISTS allergens,
+    DROP COLUMN IF EXISTS dietary_restrictions;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE recipes ADD COLUMN prompt_version VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE training_plans ADD COLUMN prompt_version VARCHAR(50) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE training_plans DROP COLUMN IF EXISTS prompt_version;
+ALTER TABLE recipes DROP COLUMN IF EXISTS prompt_version;
+-- +goose StatementEnd